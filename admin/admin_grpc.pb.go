@@ -0,0 +1,221 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: admin/admin.proto
+
+package admin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// LogAdminClient is the client API for LogAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LogAdminClient interface {
+	// GetLevels returns the global zerolog level and the level overrides of the given methods.
+	GetLevels(ctx context.Context, in *GetLevelsRequest, opts ...grpc.CallOption) (*GetLevelsResponse, error)
+	// SetLevel overrides the log level of methods matching the glob, an empty level removes the override.
+	SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error)
+	// EnablePayloadLogging enables the payload logging of methods matching the glob.
+	EnablePayloadLogging(ctx context.Context, in *EnablePayloadLoggingRequest, opts ...grpc.CallOption) (*EnablePayloadLoggingResponse, error)
+	// ListOverrides returns all active overrides.
+	ListOverrides(ctx context.Context, in *ListOverridesRequest, opts ...grpc.CallOption) (*ListOverridesResponse, error)
+}
+
+type logAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLogAdminClient(cc grpc.ClientConnInterface) LogAdminClient {
+	return &logAdminClient{cc}
+}
+
+func (c *logAdminClient) GetLevels(ctx context.Context, in *GetLevelsRequest, opts ...grpc.CallOption) (*GetLevelsResponse, error) {
+	out := new(GetLevelsResponse)
+	err := c.cc.Invoke(ctx, "/grpc_zerolog.admin.v1.LogAdmin/GetLevels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logAdminClient) SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error) {
+	out := new(SetLevelResponse)
+	err := c.cc.Invoke(ctx, "/grpc_zerolog.admin.v1.LogAdmin/SetLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logAdminClient) EnablePayloadLogging(ctx context.Context, in *EnablePayloadLoggingRequest, opts ...grpc.CallOption) (*EnablePayloadLoggingResponse, error) {
+	out := new(EnablePayloadLoggingResponse)
+	err := c.cc.Invoke(ctx, "/grpc_zerolog.admin.v1.LogAdmin/EnablePayloadLogging", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logAdminClient) ListOverrides(ctx context.Context, in *ListOverridesRequest, opts ...grpc.CallOption) (*ListOverridesResponse, error) {
+	out := new(ListOverridesResponse)
+	err := c.cc.Invoke(ctx, "/grpc_zerolog.admin.v1.LogAdmin/ListOverrides", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LogAdminServer is the server API for LogAdmin service.
+// All implementations must embed UnimplementedLogAdminServer
+// for forward compatibility
+type LogAdminServer interface {
+	// GetLevels returns the global zerolog level and the level overrides of the given methods.
+	GetLevels(context.Context, *GetLevelsRequest) (*GetLevelsResponse, error)
+	// SetLevel overrides the log level of methods matching the glob, an empty level removes the override.
+	SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error)
+	// EnablePayloadLogging enables the payload logging of methods matching the glob.
+	EnablePayloadLogging(context.Context, *EnablePayloadLoggingRequest) (*EnablePayloadLoggingResponse, error)
+	// ListOverrides returns all active overrides.
+	ListOverrides(context.Context, *ListOverridesRequest) (*ListOverridesResponse, error)
+	mustEmbedUnimplementedLogAdminServer()
+}
+
+// UnimplementedLogAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedLogAdminServer struct {
+}
+
+func (UnimplementedLogAdminServer) GetLevels(context.Context, *GetLevelsRequest) (*GetLevelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLevels not implemented")
+}
+func (UnimplementedLogAdminServer) SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLevel not implemented")
+}
+func (UnimplementedLogAdminServer) EnablePayloadLogging(context.Context, *EnablePayloadLoggingRequest) (*EnablePayloadLoggingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnablePayloadLogging not implemented")
+}
+func (UnimplementedLogAdminServer) ListOverrides(context.Context, *ListOverridesRequest) (*ListOverridesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOverrides not implemented")
+}
+func (UnimplementedLogAdminServer) mustEmbedUnimplementedLogAdminServer() {}
+
+// UnsafeLogAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LogAdminServer will
+// result in compilation errors.
+type UnsafeLogAdminServer interface {
+	mustEmbedUnimplementedLogAdminServer()
+}
+
+func RegisterLogAdminServer(s grpc.ServiceRegistrar, srv LogAdminServer) {
+	s.RegisterService(&LogAdmin_ServiceDesc, srv)
+}
+
+func _LogAdmin_GetLevels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLevelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogAdminServer).GetLevels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc_zerolog.admin.v1.LogAdmin/GetLevels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogAdminServer).GetLevels(ctx, req.(*GetLevelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogAdmin_SetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogAdminServer).SetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc_zerolog.admin.v1.LogAdmin/SetLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogAdminServer).SetLevel(ctx, req.(*SetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogAdmin_EnablePayloadLogging_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnablePayloadLoggingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogAdminServer).EnablePayloadLogging(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc_zerolog.admin.v1.LogAdmin/EnablePayloadLogging",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogAdminServer).EnablePayloadLogging(ctx, req.(*EnablePayloadLoggingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogAdmin_ListOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOverridesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogAdminServer).ListOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc_zerolog.admin.v1.LogAdmin/ListOverrides",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogAdminServer).ListOverrides(ctx, req.(*ListOverridesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LogAdmin_ServiceDesc is the grpc.ServiceDesc for LogAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LogAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grpc_zerolog.admin.v1.LogAdmin",
+	HandlerType: (*LogAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevels",
+			Handler:    _LogAdmin_GetLevels_Handler,
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    _LogAdmin_SetLevel_Handler,
+		},
+		{
+			MethodName: "EnablePayloadLogging",
+			Handler:    _LogAdmin_EnablePayloadLogging_Handler,
+		},
+		{
+			MethodName: "ListOverrides",
+			Handler:    _LogAdmin_ListOverrides_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin/admin.proto",
+}
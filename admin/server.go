@@ -0,0 +1,123 @@
+// admin provides the LogAdmin gRPC service that inspects and changes the logging of grpc_zerolog interceptors at runtime
+package admin
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative ../admin/admin.proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultTTL is the default time to live of overrides set without ttl
+var DefaultTTL = 15 * time.Minute
+
+// Register registers the LogAdmin service driving the registry on the server.
+// The service has no authorization and can enable the payload logging, which may expose personal data,
+// so register it only on a server listening on an authenticated or admin-only endpoint.
+// The overrides cannot go below zerolog.GlobalLevel: with the global level Info neither SetLevel to debug
+// nor EnablePayloadLogging of trace payloads have any effect, GetLevels reports the global level for that reason.
+func Register(s *grpc.Server, registry *grpc_zerolog.LevelRegistry) {
+	RegisterLogAdminServer(s, NewServer(registry))
+}
+
+// NewServer returns the LogAdminServer implementation driving the registry, see Register for the exposure of the service
+func NewServer(registry *grpc_zerolog.LevelRegistry) LogAdminServer {
+	return &server{registry: registry}
+}
+
+type server struct {
+	UnimplementedLogAdminServer
+	registry *grpc_zerolog.LevelRegistry
+}
+
+func (s *server) GetLevels(ctx context.Context, req *GetLevelsRequest) (*GetLevelsResponse, error) {
+	res := &GetLevelsResponse{GlobalLevel: zerolog.GlobalLevel().String()}
+	for _, m := range req.GetMethods() {
+		ml := &MethodLevel{Method: m, PayloadEnabled: s.registry.PayloadEnabled(m)}
+		if l, ok := s.registry.Level(m); ok {
+			ml.Level = l.String()
+		}
+		res.Methods = append(res.Methods, ml)
+	}
+	return res, nil
+}
+
+func (s *server) SetLevel(ctx context.Context, req *SetLevelRequest) (*SetLevelResponse, error) {
+	if req.GetLevel() == "" {
+		s.registry.Unset(req.GetMethodGlob())
+		return &SetLevelResponse{}, nil
+	}
+	level, err := zerolog.ParseLevel(req.GetLevel())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid level: %v", err)
+	}
+	ttl, err := ttlOf(req.GetTtl().AsDuration(), req.Ttl != nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.registry.SetFor(req.GetMethodGlob(), level, ttl); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid method glob: %v", err)
+	}
+	return &SetLevelResponse{Override: s.find(req.GetMethodGlob(), false)}, nil
+}
+
+func (s *server) EnablePayloadLogging(ctx context.Context, req *EnablePayloadLoggingRequest) (*EnablePayloadLoggingResponse, error) {
+	if req.GetDisable() {
+		s.registry.DisablePayload(req.GetMethodGlob())
+		return &EnablePayloadLoggingResponse{}, nil
+	}
+	ttl, err := ttlOf(req.GetTtl().AsDuration(), req.Ttl != nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.registry.EnablePayload(req.GetMethodGlob(), ttl); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid method glob: %v", err)
+	}
+	return &EnablePayloadLoggingResponse{Override: s.find(req.GetMethodGlob(), true)}, nil
+}
+
+func (s *server) ListOverrides(ctx context.Context, req *ListOverridesRequest) (*ListOverridesResponse, error) {
+	res := &ListOverridesResponse{}
+	for _, o := range s.registry.Overrides() {
+		res.Overrides = append(res.Overrides, toProto(o))
+	}
+	return res, nil
+}
+
+func (s *server) find(pattern string, payload bool) *Override {
+	for _, o := range s.registry.Overrides() {
+		if o.Pattern == pattern && o.Payload == payload {
+			return toProto(o)
+		}
+	}
+	return nil
+}
+
+func ttlOf(ttl time.Duration, set bool) (time.Duration, error) {
+	switch {
+	case !set:
+		return DefaultTTL, nil
+	case ttl <= 0:
+		return 0, status.Error(codes.InvalidArgument, "ttl must be positive")
+	default:
+		return ttl, nil
+	}
+}
+
+func toProto(o grpc_zerolog.Override) *Override {
+	ret := &Override{MethodGlob: o.Pattern, Payload: o.Payload}
+	if !o.Payload {
+		ret.Level = o.Level.String()
+	}
+	if !o.Expires.IsZero() {
+		ret.ExpireTime = timestamppb.New(o.Expires)
+	}
+	return ret
+}
@@ -0,0 +1,96 @@
+package admin_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/admin"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const method = "/payments.Payments/Charge"
+
+func TestSetLevel(t *testing.T) {
+	registry := grpc_zerolog.NewLevelRegistry()
+	s := admin.NewServer(registry)
+	ctx := context.Background()
+
+	res, err := s.SetLevel(ctx, &admin.SetLevelRequest{MethodGlob: "/payments.Payments/*", Level: "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, ok := registry.Level(method); !ok || l != zerolog.DebugLevel {
+		t.Errorf("got level %v %v, want debug", l, ok)
+	}
+	expires := res.GetOverride().GetExpireTime().AsTime()
+	if d := time.Until(expires); d <= admin.DefaultTTL-time.Minute || d > admin.DefaultTTL {
+		t.Errorf("override expires in %v, want the default ttl %v", d, admin.DefaultTTL)
+	}
+
+	if _, err := s.SetLevel(ctx, &admin.SetLevelRequest{MethodGlob: "/payments.Payments/*"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := registry.Level(method); ok {
+		t.Errorf("override not unset by empty level")
+	}
+}
+
+func TestSetLevelInvalid(t *testing.T) {
+	s := admin.NewServer(grpc_zerolog.NewLevelRegistry())
+	for name, req := range map[string]*admin.SetLevelRequest{
+		"level":    {MethodGlob: method, Level: "verbose"},
+		"glob":     {MethodGlob: "/payments.Payments/[", Level: "debug"},
+		"zero ttl": {MethodGlob: method, Level: "debug", Ttl: durationpb.New(0)},
+		"negative": {MethodGlob: method, Level: "debug", Ttl: durationpb.New(-time.Second)},
+	} {
+		if _, err := s.SetLevel(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: got %v, want InvalidArgument", name, err)
+		}
+	}
+}
+
+func TestEnablePayloadLogging(t *testing.T) {
+	registry := grpc_zerolog.NewLevelRegistry()
+	s := admin.NewServer(registry)
+	ctx := context.Background()
+
+	res, err := s.EnablePayloadLogging(ctx, &admin.EnablePayloadLoggingRequest{MethodGlob: method, Ttl: durationpb.New(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !registry.PayloadEnabled(method) || !res.GetOverride().GetPayload() {
+		t.Errorf("payload logging not enabled: %v", res)
+	}
+
+	if _, err := s.EnablePayloadLogging(ctx, &admin.EnablePayloadLoggingRequest{MethodGlob: method, Disable: true}); err != nil {
+		t.Fatal(err)
+	}
+	if registry.PayloadEnabled(method) {
+		t.Errorf("payload logging not disabled")
+	}
+}
+
+func TestListOverridesExpiry(t *testing.T) {
+	s := admin.NewServer(grpc_zerolog.NewLevelRegistry())
+	ctx := context.Background()
+	if _, err := s.SetLevel(ctx, &admin.SetLevelRequest{MethodGlob: method, Level: "debug", Ttl: durationpb.New(time.Millisecond)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SetLevel(ctx, &admin.SetLevelRequest{MethodGlob: "/payments.Payments/*", Level: "info", Ttl: durationpb.New(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	res, err := s.ListOverrides(ctx, &admin.ListOverridesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetOverrides()) != 1 || res.GetOverrides()[0].GetMethodGlob() != "/payments.Payments/*" {
+		t.Errorf("got overrides %v, want the not expired one", res.GetOverrides())
+	}
+}
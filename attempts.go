@@ -0,0 +1,163 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+const (
+	msgAttempt message = "finished attempt"
+
+	// previousAttemptsKey is the metadata key gRPC sets on retried attempts, the first attempt has no such key
+	previousAttemptsKey = "grpc-previous-rpc-attempts"
+)
+
+// errAttemptRetried is the error the Decider gets for the retried attempt which status gRPC did not report
+var errAttemptRetried = errors.New("attempt retried, status not reported")
+
+// NewAttemptHandler returns the client stats.Handler that records the attempts of calls made by the gRPC retry policy.
+// The attempts are logged by NewUnaryClientInterceptor: the call summary has the count of attempts as grpc.attempts field,
+// and if the call was retried every attempt is logged with its number as grpc.attempt field and its duration.
+// gRPC reports the status of the first and the last attempt only, the attempts in between are logged with
+// grpc.attempt.retried field and without the code and error, with Warn level. Their CallInfo has Retried set
+// and the Decider gets an error without status.
+func NewAttemptHandler() stats.Handler {
+	return attemptHandler{}
+}
+
+type attemptHandler struct{}
+
+type attemptsKey struct{}
+
+type attempt struct {
+	start, end time.Time
+	err        error
+	// reported is true if gRPC reported the status of the attempt
+	reported bool
+}
+
+// attempts of the call shared by the client interceptor and the attempt handler
+type attempts struct {
+	clock Clock
+	mu    sync.Mutex
+	list  []attempt
+}
+
+func withAttempts(ctx context.Context, clock Clock) (context.Context, *attempts) {
+	a := &attempts{clock: clock}
+	return context.WithValue(ctx, attemptsKey{}, a), a
+}
+
+// started begins the new attempt, the previous one ends if it didn't yet
+func (a *attempts) started() {
+	now := a.clock.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if n := len(a.list); n > 0 && a.list[n-1].end.IsZero() {
+		a.list[n-1].end = now
+	}
+	a.list = append(a.list, attempt{start: now})
+}
+
+func (a *attempts) ended() {
+	now := a.clock.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if n := len(a.list); n > 0 && a.list[n-1].end.IsZero() {
+		a.list[n-1].end = now
+	}
+}
+
+// reported sets the status of the first attempt, gRPC reports the end of the call for the first attempt only
+func (a *attempts) reported(err error) {
+	now := a.clock.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.list) == 0 {
+		// the attempt failed before its stream was created
+		a.list = append(a.list, attempt{start: now})
+	}
+	first := &a.list[0]
+	if first.end.IsZero() {
+		first.end = now
+	}
+	first.err, first.reported = err, true
+}
+
+// finished returns the attempts of the call, the last one ends with the error of the call
+func (a *attempts) finished(err error) []attempt {
+	now := a.clock.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	list := append([]attempt(nil), a.list...)
+	if n := len(list); n > 0 {
+		last := &list[n-1]
+		if last.end.IsZero() {
+			last.end = now
+		}
+		last.err, last.reported = err, true
+	}
+	return list
+}
+
+func (attemptHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (attemptHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	a, ok := ctx.Value(attemptsKey{}).(*attempts)
+	if !ok || !s.IsClient() {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.OutHeader:
+		a.started()
+	case *stats.InTrailer:
+		a.ended()
+	case *stats.End:
+		a.reported(s.Error)
+	}
+}
+
+func (attemptHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (attemptHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// logAttempts logs every attempt of the retried call
+func (o *options) logAttempts(log zerolog.Context, ctx context.Context, method string, list []attempt) {
+	if len(list) < 2 {
+		return
+	}
+	for i, a := range list {
+		with, decided := log.Int("grpc.attempt", i+1), a.err
+		if !a.reported {
+			with, decided = with.Bool("grpc.attempt.retried", true), errAttemptRetried
+		}
+		if !o.shouldLog(method, decided) {
+			continue
+		}
+		o.logCall(with, CallInfo{Context: ctx, FullMethod: method, Kind: CallAttempt, Client: true, Err: a.err, Start: a.start, Duration: a.end.Sub(a.start), Retried: !a.reported})
+	}
+}
+
+// logAttempt logs the attempt number of the server call from the metadata set by the client retry policy
+func logAttempt(with zerolog.Context, md metadata.MD) zerolog.Context {
+	v := md.Get(previousAttemptsKey)
+	if len(v) == 0 {
+		return with
+	}
+	previous, err := strconv.Atoi(v[0])
+	if err != nil {
+		return with
+	}
+	return with.Int("grpc.attempt", previous+1)
+}
@@ -0,0 +1,116 @@
+package grpc_zerolog_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const retryServiceConfig = `{"methodConfig": [{
+	"name": [{"service": "grpc.health.v1.Health"}],
+	"retryPolicy": {
+		"maxAttempts": 4,
+		"initialBackoff": "0.001s",
+		"maxBackoff": "0.001s",
+		"backoffMultiplier": 1,
+		"retryableStatusCodes": ["UNAVAILABLE"]
+	}
+}]}`
+
+// unavailableHealth fails the checks with Unavailable until the failures are used up
+type unavailableHealth struct {
+	healthpb.UnimplementedHealthServer
+	failures int32
+}
+
+func (h *unavailableHealth) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if atomic.AddInt32(&h.failures, -1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "not yet")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func TestAttemptsRetried(t *testing.T) {
+	if os.Getenv(isolatedEnv) == "" {
+		// gRPC reads the retry switch of its version at init, so the test runs in the process with retries on
+		cmd := isolatedCommand(t)
+		cmd.Env = append(cmd.Env, "GRPC_GO_RETRY=on")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("isolated test failed: %v\n%s", err, out)
+		}
+		return
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, &unavailableHealth{failures: 3})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	logs := grpczerologtest.NewRecorder()
+	cc, err := grpc.Dial("bufconn", append(grpc_zerolog.DialOptions(logs.Logger(), grpc_zerolog.WithAttempts(), grpc_zerolog.WithoutPayload()),
+		grpc.WithInsecure(),
+		grpc.WithDefaultServiceConfig(retryServiceConfig),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+	)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	if _, err := healthpb.NewHealthClient(cc).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts []grpczerologtest.Entry
+	var call *grpczerologtest.Entry
+	for _, e := range logs.CallEntries(checkMethod) {
+		switch e.Message {
+		case "finished attempt":
+			attempts = append(attempts, e)
+		case "finished unary call":
+			e := e
+			call = &e
+		}
+	}
+	if call == nil || call.Fields["grpc.attempts"] != float64(4) {
+		t.Fatalf("no call with 4 attempts logged, captured:\n%s", logs)
+	}
+	if len(attempts) != 4 {
+		t.Fatalf("got %d attempts, want 4, captured:\n%s", len(attempts), logs)
+	}
+	for i, e := range attempts {
+		if n := e.Fields["grpc.attempt"]; n != float64(i+1) {
+			t.Errorf("attempt %d logged as %v", i+1, n)
+		}
+		retried := e.Fields["grpc.attempt.retried"] == true
+		switch i {
+		case 0:
+			if !e.HasCode || e.Code != codes.Unavailable || retried {
+				t.Errorf("first attempt: %v", e.Fields)
+			}
+		case len(attempts) - 1:
+			if !e.HasCode || e.Code != codes.OK || retried {
+				t.Errorf("last attempt: %v", e.Fields)
+			}
+		default:
+			// gRPC doesn't report the status of attempts in between
+			if e.HasCode || !retried || e.Level != zerolog.WarnLevel {
+				t.Errorf("attempt %d: %v", i+1, e.Fields)
+			}
+			if _, ok := e.Fields[zerolog.ErrorFieldName]; ok {
+				t.Errorf("attempt %d has an error: %v", i+1, e.Fields)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// messageSizeRegexp matches the errors of gRPC when the message exceeds the size limit, including
+// "received message larger than max length allowed on current machine (%d vs. %d)"
+var messageSizeRegexp = regexp.MustCompile(`(received|send) message larger than max[^(]*\((\d+) vs\. (\d+)\)`)
+
+// callLimits are the message size limits set by the call options, zero means not set
+type callLimits struct {
+	maxRecv, maxSend int
+}
+
+// callOptions logs the compressor and codec of the client call and returns the message size limits of the call options
+func (n *FieldNames) callOptions(with zerolog.Context, opts []grpc.CallOption) (zerolog.Context, callLimits) {
+	var limits callLimits
+	compression, codec, subtype := "", "", ""
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.CompressorCallOption:
+			compression = o.CompressorType
+		case grpc.ContentSubtypeCallOption:
+			subtype = o.ContentSubtype
+		case grpc.ForceCodecCallOption:
+			codec = o.Codec.Name()
+		case grpc.CustomCodecCallOption:
+			codec = o.Codec.String()
+		case grpc.MaxRecvMsgSizeCallOption:
+			limits.maxRecv = o.MaxRecvMsgSize
+		case grpc.MaxSendMsgSizeCallOption:
+			limits.maxSend = o.MaxSendMsgSize
+		}
+	}
+	if codec == "" {
+		codec = subtype
+	}
+	return n.codec(n.compression(with, compressionName(compression)), codecName(codec)), limits
+}
+
+// serverStream logs the compressor and codec the client used for the server call
+func (n *FieldNames) serverStream(with zerolog.Context, ctx context.Context) zerolog.Context {
+	s, ok := grpc.ServerTransportStreamFromContext(ctx).(interface {
+		RecvCompress() string
+		ContentSubtype() string
+	})
+	if !ok {
+		return with
+	}
+	return n.codec(n.compression(with, compressionName(s.RecvCompress())), codecName(s.ContentSubtype()))
+}
+
+func (n *FieldNames) compression(with zerolog.Context, compression string) zerolog.Context {
+	if n.Compression == "" {
+		return with
+	}
+	return with.Str(n.Compression, compression)
+}
+
+func (n *FieldNames) codec(with zerolog.Context, codec string) zerolog.Context {
+	if n.Codec == "" {
+		return with
+	}
+	return with.Str(n.Codec, codec)
+}
+
+// compressionName returns the name of compressor, gRPC sends the messages uncompressed if it's not set
+func compressionName(compressor string) string {
+	if compressor == "" {
+		return "identity"
+	}
+	return compressor
+}
+
+// codecName returns the name of codec of content-subtype, gRPC uses proto codec if it's not set
+func codecName(subtype string) string {
+	if subtype == "" {
+		return "proto"
+	}
+	return subtype
+}
+
+// messageSize annotates the ResourceExhausted error with the size and limit of the message exceeding the limit.
+// They are parsed from the error of gRPC, otherwise the limits of call options are logged if set.
+func (n *FieldNames) messageSize(with zerolog.Context, err error, limits callLimits) zerolog.Context {
+	if status.Code(err) != codes.ResourceExhausted || n.Message == "" {
+		return with
+	}
+	if m := messageSizeRegexp.FindStringSubmatch(status.Convert(err).Message()); m != nil {
+		size, _ := strconv.Atoi(m[2])
+		limit, _ := strconv.Atoi(m[3])
+		// the limit hit by either side, the error of the server is passed to the client as is
+		limitType := "max_recv"
+		if m[1] == "send" {
+			limitType = "max_send"
+		}
+		return with.Str(n.Message+"limit_type", limitType).Int(n.Message+"size", size).Int(n.Message+"limit", limit)
+	}
+	if limits.maxRecv > 0 {
+		with = with.Int(n.Message+"max_recv_size", limits.maxRecv)
+	}
+	if limits.maxSend > 0 {
+		with = with.Int(n.Message+"max_send_size", limits.maxSend)
+	}
+	return with
+}
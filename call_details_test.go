@@ -0,0 +1,22 @@
+package grpc_zerolog
+
+import "testing"
+
+func TestMessageSizeRegexp(t *testing.T) {
+	for msg, want := range map[string][]string{
+		"grpc: received message larger than max (10 vs. 4)":                                   {"received", "10", "4"},
+		"grpc: trying to send message larger than max (10 vs. 4)":                             {"send", "10", "4"},
+		"grpc: received message larger than max length allowed on current machine (10 vs. 4)": {"received", "10", "4"},
+	} {
+		m := messageSizeRegexp.FindStringSubmatch(msg)
+		if m == nil {
+			t.Errorf("%q not matched", msg)
+			continue
+		}
+		for i, w := range want {
+			if m[i+1] != w {
+				t.Errorf("%q: got submatch %d %q, want %q", msg, i+1, m[i+1], w)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+)
+
+// CallKind is the kind of logged call statement
+type CallKind int
+
+const (
+	// CallUnary is the finished unary call of client and server interceptors
+	CallUnary CallKind = iota
+	// CallServerStream is the finished stream call of server interceptor
+	CallServerStream
+	// CallClientStream is the started stream call of client interceptor
+	CallClientStream
+	// CallStats is the finished call of stats handler
+	CallStats
+	// CallAttempt is the finished attempt of client call logged by attempt handler
+	CallAttempt
+
+	callKinds
+)
+
+// CallInfo describes the logged call for hooks
+type CallInfo struct {
+	// Context is the context of the call, it carries the metadata and values of the call
+	Context    context.Context
+	FullMethod string
+	Kind       CallKind
+	Client     bool
+	Code       codes.Code
+	Err        error
+	Start      time.Time
+	Duration   time.Duration
+	// CancelTime is the time the context of server call ended before the handler returned, zero if it did not.
+	// Without WithCancelTracking it's the time the handler returned.
+	CancelTime time.Time
+	// CancelReason is one of CancelDeadline, CancelClient and CancelShutdown if CancelTime is set
+	CancelReason string
+	// Retried is true for the CallAttempt retried by gRPC without reporting its status, Code and Err are zero then
+	Retried bool
+
+	// wallStart is the start by time package, the deadline budget is measured by it as the deadline of context is
+	wallStart time.Time
+}
+
+// CallHook adds computed fields to the call statement before it is written
+type CallHook func(e *zerolog.Event, info CallInfo)
@@ -0,0 +1,93 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// CancelDeadline is the cancel reason of the call which deadline expired
+	CancelDeadline = "deadline"
+	// CancelClient is the cancel reason of the call cancelled by client or the connection closed,
+	// proxies which time out without propagating the deadline cancel the call the same way
+	CancelClient = "client"
+	// CancelShutdown is the cancel reason of the call cancelled after the server shutdown, see WithShutdownContext
+	CancelShutdown = "shutdown"
+)
+
+// cancelWatch records when and why the context of server call ended while the handler runs
+type cancelWatch struct {
+	ctx    context.Context
+	o      *options
+	stop   chan struct{}
+	done   chan struct{}
+	at     time.Time
+	reason string
+}
+
+// watchCancel starts watching ctx, it returns nil if ctx is never done.
+// Without WithCancelTracking it starts nothing, finish checks only whether ctx ended when the handler returned.
+func (o *options) watchCancel(ctx context.Context) *cancelWatch {
+	if ctx.Done() == nil {
+		return nil
+	}
+	w := &cancelWatch{ctx: ctx, o: o}
+	if !o.cancelTracking {
+		return w
+	}
+	w.stop, w.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(w.done)
+		select {
+		case <-ctx.Done():
+			w.record()
+		case <-w.stop:
+		}
+	}()
+	return w
+}
+
+func (w *cancelWatch) record() {
+	w.at = w.o.clock.Now()
+	switch {
+	case errors.Is(w.ctx.Err(), context.DeadlineExceeded):
+		w.reason = CancelDeadline
+	case w.o.shutdown != nil && w.o.shutdown.Err() != nil:
+		w.reason = CancelShutdown
+	default:
+		w.reason = CancelClient
+	}
+}
+
+// finish stops watching and sets the cancel time and reason of info if the context ended before the handler returned
+func (w *cancelWatch) finish(info *CallInfo) {
+	if w == nil {
+		return
+	}
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+	if w.at.IsZero() && w.ctx.Err() != nil {
+		// the context ended at the same time the handler returned, or it's not tracked
+		w.record()
+	}
+	info.CancelTime, info.CancelReason = w.at, w.reason
+}
+
+// cancel logs the cancel reason, the time the context ended after start, and how long the handler kept working after it
+func (n *FieldNames) cancel(with zerolog.Context, info CallInfo) zerolog.Context {
+	if info.CancelTime.IsZero() || n.Cancel == "" {
+		return with
+	}
+	with = with.Str(n.Cancel+"reason", info.CancelReason).Time(n.Cancel+"time", info.CancelTime)
+	with = n.durationField(with, n.Cancel+"after", info.CancelTime.Sub(info.Start))
+	overrun := info.Start.Add(info.Duration).Sub(info.CancelTime)
+	if overrun < 0 {
+		overrun = 0
+	}
+	return n.durationField(with, n.Cancel+"overrun", overrun)
+}
@@ -0,0 +1,54 @@
+package grpc_zerolog_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const watchMethod = "/grpc.health.v1.Health/Watch"
+
+func TestCancelReason(t *testing.T) {
+	for name, opts := range map[string][]grpc_zerolog.Option{
+		"at finish": nil,
+		"tracked":   {grpc_zerolog.WithCancelTracking()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithLogOptions(opts...))
+			ctx, cancel := context.WithCancel(context.Background())
+			stream, err := h.HealthClient().Watch(ctx, &healthpb.HealthCheckRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := stream.Recv(); err != nil {
+				t.Fatal(err)
+			}
+			cancel()
+
+			e := waitFinishedCall(t, h.ServerLogs, watchMethod)
+			if reason := e.Fields["grpc.cancel.reason"]; reason != grpc_zerolog.CancelClient {
+				t.Errorf("got cancel reason %v, want %s\n%s", reason, grpc_zerolog.CancelClient, h.ServerLogs)
+			}
+		})
+	}
+}
+
+// waitFinishedCall waits for the call finished asynchronously to the client
+func waitFinishedCall(t *testing.T, r *grpczerologtest.Recorder, fullMethodName string) grpczerologtest.Entry {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		for _, e := range r.CallEntries(fullMethodName) {
+			if e.HasCode {
+				return e
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("no call of %s logged", fullMethodName)
+	return grpczerologtest.Entry{}
+}
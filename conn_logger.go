@@ -0,0 +1,165 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	msgConnOpen        message = "connection opened"
+	msgConnClose       message = "connection closed"
+	msgConnHandshake   message = "connection handshake failed"
+	msgConnStateChange message = "connectivity state changed"
+)
+
+// NewConnListener returns the listener that logs the accepted connections when they are opened and closed,
+// with the peer address and the lifetime. If the listener accepts *tls.Conn, e.g. made by tls.NewListener,
+// the TLS details are logged when the connection is closed. For the servers with gRPC TLS credentials use NewConnCredentials instead.
+// The lifetime is measured by the clock of WithClock and logged in the format of WithDurationFormat, other options are ignored.
+func NewConnListener(lis net.Listener, logger zerolog.Logger, opts ...Option) net.Listener {
+	return &connListener{Listener: lis, logger: logger, o: evaluateOptions(opts)}
+}
+
+type connListener struct {
+	net.Listener
+	logger zerolog.Logger
+	o      *options
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return conn, err
+	}
+	return openConn(l.o, l.logger.With().Bool("grpc.client", false), conn, nil), nil
+}
+
+// NewConnCredentials returns the credentials that log the connections when they are opened and closed,
+// with the peer address, the TLS details and the lifetime. The failed handshakes are logged as well.
+// Use it as the server credentials by grpc.Creds, or as the client ones by grpc.WithTransportCredentials.
+// The options apply as in NewConnListener.
+func NewConnCredentials(creds credentials.TransportCredentials, logger zerolog.Logger, opts ...Option) credentials.TransportCredentials {
+	return &connCredentials{TransportCredentials: creds, logger: logger, o: evaluateOptions(opts)}
+}
+
+type connCredentials struct {
+	credentials.TransportCredentials
+	logger zerolog.Logger
+	o      *options
+}
+
+func (c *connCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.TransportCredentials.ServerHandshake(rawConn)
+	return c.handshaked(c.logger.With().Bool("grpc.client", false), rawConn, conn, info, err)
+}
+
+func (c *connCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	return c.handshaked(c.logger.With().Bool("grpc.client", true).Str("grpc.authority", authority), rawConn, conn, info, err)
+}
+
+func (c *connCredentials) handshaked(with zerolog.Context, rawConn, conn net.Conn, info credentials.AuthInfo, err error) (net.Conn, credentials.AuthInfo, error) {
+	if err != nil {
+		logHandshakeFailure(c.o.fields.addresses(with, rawConn.RemoteAddr(), rawConn.LocalAddr()), err)
+		return conn, info, err
+	}
+	var state *tls.ConnectionState
+	if t, ok := info.(credentials.TLSInfo); ok {
+		state = &t.State
+	}
+	return openConn(c.o, with, conn, state), info, nil
+}
+
+func (c *connCredentials) Clone() credentials.TransportCredentials {
+	return &connCredentials{TransportCredentials: c.TransportCredentials.Clone(), logger: c.logger, o: c.o}
+}
+
+type loggedConn struct {
+	net.Conn
+	logger zerolog.Logger
+	o      *options
+	start  time.Time
+	once   sync.Once
+}
+
+func openConn(o *options, with zerolog.Context, conn net.Conn, state *tls.ConnectionState) net.Conn {
+	with = o.fields.addresses(with, conn.RemoteAddr(), conn.LocalAddr())
+	if state != nil {
+		with = tlsLog(with, state)
+	}
+	l := with.Logger()
+	l.Info().Msg(string(msgConnOpen))
+	return &loggedConn{Conn: conn, logger: l, o: o, start: o.clock.Now()}
+}
+
+func (c *loggedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		with := millisField(c.logger.With(), "grpc.conn.time_ms", c.o.clock.Since(c.start))
+		if t, ok := c.Conn.(*tls.Conn); ok {
+			if s := t.ConnectionState(); s.HandshakeComplete {
+				with = tlsLog(with, &s)
+			}
+		}
+		l := with.Logger()
+		l.Info().Msg(string(msgConnClose))
+	})
+	return err
+}
+
+func logHandshakeFailure(with zerolog.Context, err error) {
+	l := with.Logger()
+	l.Warn().Err(err).Msg(string(msgConnHandshake))
+}
+
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "1.0",
+	tls.VersionTLS11: "1.1",
+	tls.VersionTLS12: "1.2",
+	tls.VersionTLS13: "1.3",
+}
+
+func tlsLog(with zerolog.Context, s *tls.ConnectionState) zerolog.Context {
+	with = with.
+		Str("tls.version", tlsVersions[s.Version]).
+		Str("tls.cipher", tls.CipherSuiteName(s.CipherSuite)).
+		Bool("tls.resumed", s.DidResume)
+	if s.ServerName != "" {
+		with = with.Str("tls.server_name", s.ServerName)
+	}
+	if s.NegotiatedProtocol != "" {
+		with = with.Str("tls.negotiated_protocol", s.NegotiatedProtocol)
+	}
+	if len(s.PeerCertificates) > 0 {
+		with = with.Str("tls.peer.subject", s.PeerCertificates[0].Subject.String())
+	}
+	return with
+}
+
+// WatchConnectivity logs the connectivity state transitions of the client connection until ctx is done or the connection is closed.
+// The TransientFailure state is logged with Warn level, others with Info level. Run it in a separate goroutine.
+func WatchConnectivity(ctx context.Context, cc *grpc.ClientConn, logger zerolog.Logger) {
+	l := logger.With().Str("grpc.target", cc.Target()).Logger()
+	prev := cc.GetState()
+	l.Info().Str("grpc.state", prev.String()).Msg(string(msgConnStateChange))
+	for prev != connectivity.Shutdown && cc.WaitForStateChange(ctx, prev) {
+		state := cc.GetState()
+		level := zerolog.InfoLevel
+		if state == connectivity.TransientFailure {
+			level = zerolog.WarnLevel
+		}
+		l.WithLevel(level).
+			Str("grpc.state", state.String()).
+			Str("grpc.previous_state", prev.String()).
+			Msg(string(msgConnStateChange))
+		prev = state
+	}
+}
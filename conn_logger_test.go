@@ -0,0 +1,56 @@
+package grpc_zerolog_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestConnCredentialsClient(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	defer s.Stop()
+
+	logs := grpczerologtest.NewRecorder()
+	cc, err := grpc.Dial("bufconn",
+		grpc.WithTransportCredentials(grpc_zerolog.NewConnCredentials(insecure.NewCredentials(), logs.Logger())),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := healthpb.NewHealthClient(cc).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	cc.Close()
+
+	// the transport closes the connection asynchronously
+	var opened, closed bool
+	for deadline := time.Now().Add(time.Second); !closed && time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		for _, e := range logs.Entries() {
+			if e.Fields["grpc.client"] != true || e.Fields["grpc.authority"] != "bufconn" {
+				continue
+			}
+			switch e.Message {
+			case "connection opened":
+				opened = true
+			case "connection closed":
+				_, closed = e.Fields["grpc.conn.time_ms"]
+			}
+		}
+	}
+	if !opened || !closed {
+		t.Errorf("client connection not logged, opened %v, closed %v: %+v", opened, closed, logs.Entries())
+	}
+}
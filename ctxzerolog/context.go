@@ -3,6 +3,7 @@ package ctxzerolog
 
 import (
 	"context"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -11,12 +12,50 @@ type ctxKey struct{}
 
 var key ctxKey
 
+// Clock provides the time for durations of scopes, it's the same as grpc_zerolog.Clock
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
 type wrapper struct {
 	logger zerolog.Logger
+	// limits are the LimitPerCall buckets shared by the contexts derived by Scope and Detach
+	limits *buckets
+	clock  Clock
+	// scope is the path of the scope names joined by "/", unscoped is the logger without the scope field
+	scope    string
+	unscoped zerolog.Logger
 }
 
 func New(ctx context.Context, log zerolog.Logger) context.Context {
-	return context.WithValue(ctx, key, &wrapper{log})
+	return NewWithClock(ctx, log, systemClock{})
+}
+
+// NewWithClock stores the logger in ctx, the durations of scopes derived from the returned context are measured by the clock
+func NewWithClock(ctx context.Context, log zerolog.Logger, clock Clock) context.Context {
+	return context.WithValue(ctx, key, &wrapper{logger: log, limits: newBuckets(), clock: clock})
+}
+
+// derive returns the context carrying the logger which shares the per call limits and the clock with the logger stored in parent
+func derive(ctx, parent context.Context, log zerolog.Logger) (context.Context, *wrapper) {
+	w := &wrapper{logger: log}
+	if p, ok := parent.Value(key).(*wrapper); ok && p != nil {
+		w.limits, w.clock = p.limits, p.clock
+	} else {
+		w.limits, w.clock = newBuckets(), systemClock{}
+	}
+	return context.WithValue(ctx, key, w), w
 }
 
 func Set(ctx context.Context, changes zerolog.Context) {
@@ -34,3 +73,13 @@ func Get(ctx context.Context) zerolog.Context {
 	}
 	return l.logger.With()
 }
+
+// Detach returns a background context carrying a copy of the logger stored in ctx, marked with grpc.detached=true.
+// Use it for work that outlives the gRPC call, the changes made by Set are not shared between ctx and the returned context, the LimitPerCall limits are.
+func Detach(ctx context.Context) context.Context {
+	detached, w := derive(context.Background(), ctx, Get(ctx).Bool("grpc.detached", true).Logger())
+	if p, ok := ctx.Value(key).(*wrapper); ok && p != nil && p.scope != "" {
+		w.scope, w.unscoped = p.scope, p.unscoped.With().Bool("grpc.detached", true).Logger()
+	}
+	return detached
+}
@@ -0,0 +1,147 @@
+package ctxzerolog
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+// LimitScope defines which messages share the same limit
+type LimitScope int
+
+const (
+	// LimitPerCall limits the messages of every gRPC call separately, the contexts derived by Scope and Detach share the limit of the call
+	LimitPerCall LimitScope = iota
+	// LimitPerMethod limits the messages of all calls of the same gRPC method together
+	LimitPerMethod
+	// LimitGlobal limits the messages of all calls together
+	LimitGlobal
+)
+
+// Limiter suppresses the messages with the same key exceeding the burst during the interval.
+// The count of suppressed messages is logged as a summary when the interval ends or on Flush,
+// the state of the key is dropped at the end of the interval.
+type Limiter struct {
+	scope    LimitScope
+	burst    int
+	interval time.Duration
+
+	buckets *buckets
+}
+
+type limitKey struct {
+	limiter *Limiter
+	method  string
+	key     string
+}
+
+type bucket struct {
+	count      int
+	suppressed int
+	level      zerolog.Level
+	// logger is the logger of the last message, the summary is logged with it
+	logger zerolog.Logger
+}
+
+// buckets of the limiter scope
+type buckets struct {
+	mu sync.Mutex
+	m  map[limitKey]*bucket
+}
+
+func newBuckets() *buckets {
+	return &buckets{m: make(map[limitKey]*bucket)}
+}
+
+// NewLimiter returns a Limiter that allows up to burst messages with the same key in every interval
+func NewLimiter(scope LimitScope, burst int, interval time.Duration) *Limiter {
+	return &Limiter{
+		scope:    scope,
+		burst:    burst,
+		interval: interval,
+		buckets:  newBuckets(),
+	}
+}
+
+// Event starts a new message with the given level on the logger stored in ctx.
+// It returns nil if the message identified by key is suppressed, it's safe to call the methods of nil *zerolog.Event.
+func (l *Limiter) Event(ctx context.Context, level zerolog.Level, key string) *zerolog.Event {
+	logger := Get(ctx).Logger()
+	if !l.take(ctx, key, level, logger) {
+		return nil
+	}
+	return logger.WithLevel(level)
+}
+
+// Flush logs the summaries of messages suppressed so far in the scope visible from ctx
+func (l *Limiter) Flush(ctx context.Context) {
+	logger := Get(ctx).Logger()
+	bs, method := l.bucketsOf(ctx)
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	for k, b := range bs.m {
+		if k.limiter != l || k.method != method || b.suppressed == 0 {
+			continue
+		}
+		logSuppressed(logger, b.level, k.key, b.suppressed)
+		b.suppressed = 0
+	}
+}
+
+func (l *Limiter) take(ctx context.Context, key string, level zerolog.Level, logger zerolog.Logger) bool {
+	bs, method := l.bucketsOf(ctx)
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	k := limitKey{limiter: l, method: method, key: key}
+	b, ok := bs.m[k]
+	if !ok {
+		b = &bucket{}
+		bs.m[k] = b
+		time.AfterFunc(l.interval, func() { bs.expire(k) })
+	}
+	b.level, b.logger = level, logger
+
+	if b.count < l.burst {
+		b.count++
+		return true
+	}
+	b.suppressed++
+	return false
+}
+
+// expire drops the bucket at the end of the interval and logs its summary
+func (bs *buckets) expire(k limitKey) {
+	bs.mu.Lock()
+	b := bs.m[k]
+	delete(bs.m, k)
+	bs.mu.Unlock()
+	if b != nil && b.suppressed > 0 {
+		logSuppressed(b.logger, b.level, k.key, b.suppressed)
+	}
+}
+
+// bucketsOf returns the buckets shared in the limiter scope and the method of LimitPerMethod scope
+func (l *Limiter) bucketsOf(ctx context.Context) (*buckets, string) {
+	switch l.scope {
+	case LimitPerCall:
+		if w, ok := ctx.Value(key).(*wrapper); ok && w != nil {
+			return w.limits, ""
+		}
+	case LimitPerMethod:
+		if method, ok := grpc.Method(ctx); ok {
+			return l.buckets, method
+		}
+	}
+	return l.buckets, ""
+}
+
+func logSuppressed(logger zerolog.Logger, level zerolog.Level, key string, suppressed int) {
+	logger.WithLevel(level).
+		Str("limit.key", key).
+		Int("limit.suppressed", suppressed).
+		Msgf("suppressed %d similar messages", suppressed)
+}
@@ -0,0 +1,74 @@
+package ctxzerolog_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog/ctxzerolog"
+	"github.com/rs/zerolog"
+)
+
+func TestLimiterPerCallSharedByScope(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxzerolog.New(context.Background(), zerolog.New(zerolog.SyncWriter(&buf)))
+	limiter := ctxzerolog.NewLimiter(ctxzerolog.LimitPerCall, 1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		limiter.Event(ctx, zerolog.InfoLevel, "hot").Msg("hot")
+	}
+	scoped, finish := ctxzerolog.Scope(ctx, "child", nil)
+	if e := limiter.Event(scoped, zerolog.InfoLevel, "hot"); e != nil {
+		t.Errorf("message in scope escaped the limit of the call")
+	}
+	finish(nil)
+	if e := limiter.Event(ctxzerolog.Detach(ctx), zerolog.InfoLevel, "hot"); e != nil {
+		t.Errorf("detached message escaped the limit of the call")
+	}
+
+	if n := strings.Count(buf.String(), `"message":"hot"`); n != 1 {
+		t.Errorf("got %d messages, want 1", n)
+	}
+}
+
+// syncBuffer is the buffer safe to read while the limiter timers write to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLimiterSummaryAtIntervalEnd(t *testing.T) {
+	var buf syncBuffer
+	ctx := ctxzerolog.New(context.Background(), zerolog.New(&buf))
+	limiter := ctxzerolog.NewLimiter(ctxzerolog.LimitGlobal, 1, 10*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		limiter.Event(ctx, zerolog.InfoLevel, "burst").Msg("burst")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), `"limit.suppressed":2`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("summary not logged after interval, got %s", buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if e := limiter.Event(ctx, zerolog.InfoLevel, "burst"); e == nil {
+		t.Errorf("message suppressed in the next interval")
+	}
+}
@@ -0,0 +1,41 @@
+package ctxzerolog
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const msgScope = "finished scope"
+
+// Scope derives a child logger named by name and extended by fields from the logger stored in ctx.
+// The nested scopes are logged with one scope field holding the path of names, like "load/query".
+// The returned context carries the child logger, so Set calls on it do not affect the parent,
+// nor the scopes nested in it, which are derived from the fields the scope was created with.
+// The returned function logs the finished scope with its duration measured by the clock given to NewWithClock,
+// the error is logged if not nil.
+func Scope(ctx context.Context, name string, fields map[string]interface{}) (context.Context, func(err error)) {
+	with, path := Get(ctx), name
+	if p, ok := ctx.Value(key).(*wrapper); ok && p != nil && p.scope != "" {
+		with, path = p.unscoped.With(), p.scope+"/"+name
+	}
+	if len(fields) > 0 {
+		with = with.Fields(fields)
+	}
+	unscoped := with.Logger()
+	scoped, w := derive(ctx, ctx, unscoped.With().Str("scope", path).Logger())
+	w.scope, w.unscoped = path, unscoped
+	start := w.clock.Now()
+
+	return scoped, func(err error) {
+		with := w.logger.With().Float64("scope.time_ms", float64(w.clock.Since(start))/float64(time.Millisecond))
+		level := zerolog.InfoLevel
+		if err != nil {
+			with = with.Err(err)
+			level = zerolog.ErrorLevel
+		}
+		l := with.Logger()
+		l.WithLevel(level).Msg(msgScope)
+	}
+}
@@ -0,0 +1,46 @@
+package ctxzerolog_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog/ctxzerolog"
+	"github.com/rs/zerolog"
+)
+
+// stepClock advances by the step on every Now call
+type stepClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+func (c *stepClock) Since(t time.Time) time.Duration {
+	return c.now.Sub(t)
+}
+
+func TestScopeNestedPath(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxzerolog.NewWithClock(context.Background(), zerolog.New(&buf), &stepClock{step: 5 * time.Millisecond})
+
+	scoped, finish := ctxzerolog.Scope(ctx, "outer", map[string]interface{}{"user": "u1"})
+	inner, finishInner := ctxzerolog.Scope(scoped, "inner", nil)
+	l := ctxzerolog.Get(ctxzerolog.Detach(inner)).Logger()
+	l.Info().Msg("detached")
+	finishInner(nil)
+	finish(nil)
+
+	want := `{"level":"info","user":"u1","scope":"outer/inner","grpc.detached":true,"message":"detached"}` + "\n" +
+		`{"level":"info","user":"u1","scope":"outer/inner","scope.time_ms":5,"message":"finished scope"}` + "\n" +
+		`{"level":"info","user":"u1","scope":"outer","scope.time_ms":10,"message":"finished scope"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
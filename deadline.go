@@ -0,0 +1,44 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+)
+
+// deadline logs the deadline budget of the call and the diagnostics of DeadlineExceeded and Canceled codes.
+// The budget is the time remaining to the deadline at start, the remaining is the time left at finish,
+// both are logged in the DurationFormat and measured by the wall time, not the Clock, as the deadline of context is.
+// The cause of the failed call is "deadline" or "cancel",
+// the side is "client" when the context of the call ended, and "server" when the call ended by the other deadline or cancellation.
+func (n *FieldNames) deadline(with zerolog.Context, info CallInfo) zerolog.Context {
+	if n.DeadlineDetails == "" {
+		return with
+	}
+	if d, ok := info.Context.Deadline(); ok && !info.wallStart.IsZero() {
+		budget, remaining := d.Sub(info.wallStart), time.Until(d)
+		with = n.durationField(with, n.DeadlineDetails+"budget", budget)
+		with = n.durationField(with, n.DeadlineDetails+"remaining", remaining)
+		if budget > 0 {
+			with = with.Float64(n.DeadlineDetails+"used_pct", float64(budget-remaining)/float64(budget)*100)
+		}
+	}
+
+	if info.Code != codes.DeadlineExceeded && info.Code != codes.Canceled {
+		return with
+	}
+	ctxErr := info.Context.Err()
+	cause, side := "cancel", "server"
+	switch {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		cause, side = "deadline", "client"
+	case errors.Is(ctxErr, context.Canceled):
+		side = "client"
+	case info.Code == codes.DeadlineExceeded:
+		cause = "deadline"
+	}
+	return with.Str(n.DeadlineDetails+"cause", cause).Str(n.DeadlineDetails+"side", side)
+}
@@ -1,11 +1,14 @@
 package grpc_zerolog_test
 
 import (
+	"context"
 	"net"
 	"os"
 	"path"
+	"time"
 
 	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/admin"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
@@ -185,3 +188,209 @@ func Example_clientInitializationSimple() {
 	defer conn.Close()
 
 }
+
+func ExampleWithLevelRegistry() {
+	levels := grpc_zerolog.NewLevelRegistry()
+	_ = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpc_zerolog.NewUnaryServerInterceptor(log.Logger, grpc_zerolog.WithLevelRegistry(levels)),
+		),
+	)
+
+	// later, at runtime, during an incident
+	if err := levels.Set("/payments.Payments/*", zerolog.DebugLevel); err != nil {
+		log.Error().Err(err).Msg("invalid pattern")
+	}
+}
+
+func Example_adminService() {
+	levels := grpc_zerolog.NewLevelRegistry()
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpc_zerolog.NewPayloadUnaryServerInterceptor(log.Logger, grpc_zerolog.WithPayloadRegistry(levels)),
+			grpc_zerolog.NewUnaryServerInterceptor(log.Logger, grpc_zerolog.WithLevelRegistry(levels)),
+		),
+	)
+
+	// the levels and payload logging are changed by LogAdmin calls now
+	admin.Register(s, levels)
+	// pb.Register .... your service (s, my_service.New(opts...))
+}
+
+func ExampleReplaceGrpcLogger() {
+	restore := grpc_zerolog.ReplaceGrpcLogger(
+		zerolog.New(os.Stderr).Level(zerolog.WarnLevel),
+		grpc_zerolog.WithBridgeFatalHandler(zerolog.ErrorLevel, func(msg string) {
+			// gRPC exits right after, run the shutdown hooks here
+		}),
+	)
+	// restore the previous gRPC logger, e.g. at the end of test
+	defer restore()
+}
+
+func ExampleOpenPolicyFile() {
+	// the admin service overrides the levels of the policy at runtime
+	levels := grpc_zerolog.NewLevelRegistry()
+	policy, err := grpc_zerolog.OpenPolicyFile("/etc/grpc-logging/policy.yaml", levels)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot load logging policy")
+	}
+	go policy.Watch(context.Background(), 10*time.Second, func(err error) {
+		log.Error().Err(err).Msg("cannot reload logging policy")
+	})
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpc_zerolog.NewPayloadUnaryServerInterceptor(log.Logger, policy.PayloadOptions()...),
+			grpc_zerolog.NewUnaryServerInterceptor(log.Logger, policy.Options()...),
+		),
+	)
+	admin.Register(server, levels)
+}
+
+func ExampleMatcher() {
+	infra := grpc_zerolog.Or(grpc_zerolog.HealthMatcher, grpc_zerolog.ReflectionMatcher)
+	payments := grpc_zerolog.MatchPackage("payments")
+
+	_ = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpc_zerolog.NewPayloadUnaryServerInterceptor(
+				log.Logger,
+				grpc_zerolog.WithPayloadDecider(payments.PayloadDecider()),
+			),
+			grpc_zerolog.NewUnaryServerInterceptor(
+				log.Logger,
+				grpc_zerolog.WithDecider(grpc_zerolog.Not(infra).Decider()),
+				grpc_zerolog.WithLevelRules(payments.Level(zerolog.DebugLevel)),
+			),
+		),
+	)
+}
+
+func ExampleServerOptions() {
+	var restore func()
+	opts := grpc_zerolog.ServerOptions(
+		log.Logger,
+		grpc_zerolog.WithLogOptions(grpc_zerolog.WithInfraSuppression(time.Minute)),
+		grpc_zerolog.WithPayloadOptions(grpc_zerolog.WithPayloadLevel(zerolog.DebugLevel)),
+		grpc_zerolog.WithGrpcLogger(&restore, grpc_zerolog.WithBridgeVerbosity(0)),
+	)
+	defer restore()
+	_ = grpc.NewServer(opts...)
+}
+
+func ExampleDialOptions() {
+	addr := "localhost:9000"
+	opts := append(grpc_zerolog.DialOptions(log.Logger, grpc_zerolog.WithoutPayload()), grpc.WithInsecure())
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		log.Fatal().Err(err).Str("addr", addr).Send()
+	}
+	defer conn.Close()
+}
+
+func ExampleNewStatsHandler() {
+	_ = grpc.NewServer(
+		grpc.StatsHandler(grpc_zerolog.NewStatsHandler(log.Logger, grpc_zerolog.WithDecider(customDecider))),
+	)
+}
+
+func ExampleWatchConnectivity() {
+	addr := "localhost:9000"
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		log.Fatal().Err(err).Str("addr", addr).Send()
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go grpc_zerolog.WatchConnectivity(ctx, conn, log.Logger)
+}
+
+func ExampleNewConnListener() {
+	addr := ":9000"
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal().Err(err).Str("addr", addr).Msg("cannont listen")
+	}
+
+	s := grpc.NewServer()
+	if err := s.Serve(grpc_zerolog.NewConnListener(lis, log.Logger)); err != nil {
+		log.Fatal().Err(err).Msg("failed to serve")
+	}
+}
+
+func ExampleWithFieldNames() {
+	_ = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpc_zerolog.NewPayloadUnaryServerInterceptor(log.Logger, grpc_zerolog.WithPayloadFieldNames(grpc_zerolog.OTelFieldNames)),
+			grpc_zerolog.NewUnaryServerInterceptor(log.Logger,
+				grpc_zerolog.WithFieldNames(grpc_zerolog.OTelFieldNames),
+				grpc_zerolog.WithDurationFormat(grpc_zerolog.DurationNanos),
+			),
+		),
+	)
+}
+
+func ExampleWithHook() {
+	_ = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpc_zerolog.NewUnaryServerInterceptor(log.Logger,
+				grpc_zerolog.WithMessage(grpc_zerolog.CallUnary, "rpc finished"),
+				grpc_zerolog.WithHook(func(e *zerolog.Event, info grpc_zerolog.CallInfo) {
+					e.Bool("slo.met", info.Duration < 100*time.Millisecond)
+				}),
+			),
+		),
+	)
+}
+
+func ExampleWithShutdownContext() {
+	shutdown, stop := context.WithCancel(context.Background())
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpc_zerolog.NewUnaryServerInterceptor(log.Logger, grpc_zerolog.WithShutdownContext(shutdown)),
+		),
+	)
+
+	// on shutdown, calls cancelled by Stop are logged with grpc.cancel.reason=shutdown
+	stop()
+	server.Stop()
+}
+
+func ExampleNewAttemptHandler() {
+	_, _ = grpc.Dial("localhost:50051",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(grpc_zerolog.NewUnaryClientInterceptor(log.Logger)),
+		grpc.WithStatsHandler(grpc_zerolog.NewAttemptHandler()),
+	)
+}
+
+func ExampleNewRetryUnaryClientInterceptor() {
+	_, _ = grpc.Dial("localhost:50051",
+		grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(
+			grpc_zerolog.NewUnaryClientInterceptor(log.Logger),
+			grpc_zerolog.NewRetryUnaryClientInterceptor(log.Logger,
+				grpc_zerolog.WithRetryCodes(codes.Unavailable, codes.ResourceExhausted),
+				grpc_zerolog.WithRetryMaxAttempts(5),
+			),
+		),
+	)
+}
+
+func ExampleNewUnaryClientInterceptor_compression() {
+	conn, err := grpc.Dial("localhost:50051",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(grpc_zerolog.NewUnaryClientInterceptor(log.Logger)),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1<<20)),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to dial")
+	}
+	defer conn.Close()
+
+	// the finished calls are logged with grpc.compression and grpc.codec fields,
+	// the ResourceExhausted failures with grpc.message.size and grpc.message.limit fields
+}
@@ -0,0 +1,180 @@
+package grpc_zerolog
+
+import (
+	"net"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+)
+
+// DurationFormat defines how the call duration is logged
+type DurationFormat int
+
+const (
+	// DurationMillis logs the duration as float number of milliseconds
+	DurationMillis DurationFormat = iota
+	// DurationNanos logs the duration as integer number of nanoseconds
+	DurationNanos
+	// DurationString logs the duration as string like "1.5ms"
+	DurationString
+)
+
+// FieldNames defines the names of call fields logged by interceptors, the empty name means the zerolog default for Error.
+// The details of calls are named by the names and prefixes below Error, the empty name or prefix omits the details.
+type FieldNames struct {
+	// System is logged with constant value "grpc" if not empty
+	System  string
+	Service string
+	Method  string
+	Code    string
+	// NumericCode logs the code as number instead of name
+	NumericCode    bool
+	Duration       string
+	DurationFormat DurationFormat
+	Deadline       string
+	Error          string
+
+	// DeadlineDetails is the prefix of budget, remaining, used_pct, cause and side fields of the calls with deadline
+	DeadlineDetails string
+	// Cancel is the prefix of reason, time, after and overrun fields of the server calls cancelled before the handler returned
+	Cancel      string
+	Compression string
+	Codec       string
+	// Message is the prefix of limit_type, size, limit, max_recv_size and max_send_size fields of the calls failed with ResourceExhausted
+	Message string
+	// PeerAddress and LocalAddress are the addresses of connections and of calls logged by the stats handler
+	PeerAddress  string
+	LocalAddress string
+	// Stats is the prefix of request.wire_length, response.wire_length, request.messages, response.messages,
+	// header.wire_length and trailer.wire_length fields logged by the stats handler
+	Stats string
+}
+
+var (
+	// DefaultFieldNames are the field names used by default
+	DefaultFieldNames = FieldNames{
+		Service:        "grpc.service",
+		Method:         "grpc.method",
+		Code:           "grpc.code",
+		Duration:       "grpc.time_ms",
+		DurationFormat: DurationMillis,
+		Deadline:       "grpc.request.deadline",
+
+		DeadlineDetails: "grpc.deadline.",
+		Cancel:          "grpc.cancel.",
+		Compression:     "grpc.compression",
+		Codec:           "grpc.codec",
+		Message:         "grpc.message.",
+		PeerAddress:     "grpc.peer.address",
+		LocalAddress:    "grpc.local.address",
+		Stats:           "grpc.",
+	}
+
+	// ECSFieldNames are the field names of Elastic Common Schema, which adopted the OpenTelemetry rpc fields,
+	// the duration is logged as event.duration in nanoseconds. ECS has no fields for the details, they keep the default names
+	ECSFieldNames = FieldNames{
+		System:         "rpc.system",
+		Service:        "rpc.service",
+		Method:         "rpc.method",
+		Code:           "rpc.grpc.status_code",
+		NumericCode:    true,
+		Duration:       "event.duration",
+		DurationFormat: DurationNanos,
+		Deadline:       "grpc.request.deadline",
+		Error:          "error.message",
+
+		DeadlineDetails: "grpc.deadline.",
+		Cancel:          "grpc.cancel.",
+		Compression:     "grpc.compression",
+		Codec:           "grpc.codec",
+		Message:         "grpc.message.",
+		PeerAddress:     "grpc.peer.address",
+		LocalAddress:    "grpc.local.address",
+		Stats:           "grpc.",
+	}
+
+	// OTelFieldNames are the field names of OpenTelemetry semantic conventions for RPC,
+	// the details are named in the rpc.grpc namespace as the conventions have no fields for them
+	OTelFieldNames = FieldNames{
+		System:         "rpc.system",
+		Service:        "rpc.service",
+		Method:         "rpc.method",
+		Code:           "rpc.grpc.status_code",
+		NumericCode:    true,
+		Duration:       "rpc.duration_ms",
+		DurationFormat: DurationMillis,
+		Deadline:       "rpc.grpc.deadline",
+		Error:          "exception.message",
+
+		DeadlineDetails: "rpc.grpc.deadline.",
+		Cancel:          "rpc.grpc.cancel.",
+		Compression:     "rpc.grpc.compression",
+		Codec:           "rpc.grpc.codec",
+		Message:         "rpc.grpc.message.",
+		PeerAddress:     "rpc.grpc.peer.address",
+		LocalAddress:    "rpc.grpc.local.address",
+		Stats:           "rpc.grpc.",
+	}
+)
+
+func (n *FieldNames) call(with zerolog.Context, service, method string) zerolog.Context {
+	if n.System != "" {
+		with = with.Str(n.System, "grpc")
+	}
+	return with.Str(n.Service, service).Str(n.Method, method)
+}
+
+func (n *FieldNames) code(with zerolog.Context, code codes.Code) zerolog.Context {
+	if n.NumericCode {
+		return with.Int(n.Code, int(code))
+	}
+	return with.Str(n.Code, code.String())
+}
+
+func (n *FieldNames) duration(with zerolog.Context, d time.Duration) zerolog.Context {
+	return n.durationField(with, n.Duration, d)
+}
+
+// durationField logs the duration d as name field in the DurationFormat
+func (n *FieldNames) durationField(with zerolog.Context, name string, d time.Duration) zerolog.Context {
+	switch n.DurationFormat {
+	case DurationNanos:
+		return with.Int64(name, d.Nanoseconds())
+	case DurationString:
+		return with.Str(name, d.String())
+	default:
+		return millisField(with, name, d)
+	}
+}
+
+// millisField logs the duration d as name field in float milliseconds whatever the DurationFormat is, it's for the fields with _ms suffix
+func millisField(with zerolog.Context, name string, d time.Duration) zerolog.Context {
+	return with.Float64(name, float64(d)/float64(time.Millisecond))
+}
+
+// addresses logs the peer and local addresses, nil addresses are omitted
+func (n *FieldNames) addresses(with zerolog.Context, peer, local net.Addr) zerolog.Context {
+	if n.PeerAddress != "" && peer != nil {
+		with = with.Str(n.PeerAddress, peer.String())
+	}
+	if n.LocalAddress != "" && local != nil {
+		with = with.Str(n.LocalAddress, local.String())
+	}
+	return with
+}
+
+// stats logs the stats handler field name prefixed by Stats
+func (n *FieldNames) stats(with zerolog.Context, name string, v int64) zerolog.Context {
+	if n.Stats == "" {
+		return with
+	}
+	return with.Int64(n.Stats+name, v)
+}
+
+func (n *FieldNames) err(with zerolog.Context, err error) zerolog.Context {
+	if n.Error == "" {
+		return with.Err(err)
+	}
+	return with.AnErr(n.Error, err)
+}
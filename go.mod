@@ -6,4 +6,6 @@ require (
 	github.com/golang/protobuf v1.4.3
 	github.com/rs/zerolog v1.20.0
 	google.golang.org/grpc v1.35.0
+	google.golang.org/protobuf v1.25.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -2,93 +2,217 @@ package grpc_zerolog
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/grpclog"
 )
 
-func ReplaceGrpcLogger(logger zerolog.Logger) {
-	grpclog.SetLoggerV2(wrap(logger))
+var (
+	grpcLoggerMu sync.Mutex
+	// grpcLogger is the logger set by ReplaceGrpcLogger, nil means the gRPC default logger
+	grpcLogger grpclog.LoggerV2
+)
+
+// ReplaceGrpcLogger sets the zerolog logger as the logger of gRPC internals.
+// It returns the function restoring the logger replaced by this call, the restore functions should be called in reverse order.
+// Only the loggers set by ReplaceGrpcLogger can be restored, grpclog has no getter of the current logger:
+// if the replaced logger was set by grpclog.SetLoggerV2 or is the default one, the restore sets a new logger
+// configured by GRPC_GO_LOG_SEVERITY_LEVEL and GRPC_GO_LOG_VERBOSITY_LEVEL the same way as the gRPC default.
+// As grpclog.SetLoggerV2, it is not mutex-protected against the gRPC logging and should be called before any gRPC functions.
+func ReplaceGrpcLogger(logger zerolog.Logger, opts ...BridgeOption) (restore func()) {
+	grpcLoggerMu.Lock()
+	defer grpcLoggerMu.Unlock()
+
+	prev := grpcLogger
+	setGrpcLogger(wrap(logger, evaluateBridgeOptions(opts)))
+	return func() {
+		grpcLoggerMu.Lock()
+		defer grpcLoggerMu.Unlock()
+		if b, ok := grpcLogger.(*bridge); ok {
+			b.limits.flush()
+		}
+		setGrpcLogger(prev)
+	}
+}
+
+func setGrpcLogger(l grpclog.LoggerV2) {
+	grpcLogger = l
+	if l == nil {
+		l = defaultGrpcLogger()
+	}
+	grpclog.SetLoggerV2(l)
+}
+
+// defaultGrpcLogger returns the logger configured the same way as gRPC default one
+func defaultGrpcLogger() grpclog.LoggerV2 {
+	errorW := ioutil.Discard
+	warningW := ioutil.Discard
+	infoW := ioutil.Discard
+
+	switch os.Getenv("GRPC_GO_LOG_SEVERITY_LEVEL") {
+	case "", "ERROR", "error":
+		errorW = os.Stderr
+	case "WARNING", "warning":
+		warningW = os.Stderr
+	case "INFO", "info":
+		infoW = os.Stderr
+	}
+
+	v, _ := strconv.Atoi(os.Getenv("GRPC_GO_LOG_VERBOSITY_LEVEL"))
+	return grpclog.NewLoggerV2WithVerbosity(infoW, warningW, errorW, v)
 }
 
-func wrap(l zerolog.Logger) *bridge {
-	return &bridge{l}
+func wrap(l zerolog.Logger, o *bridgeOptions) *bridge {
+	if len(o.fields) > 0 {
+		l = l.With().Fields(o.fields).Logger()
+	}
+	return &bridge{logger: l, opts: o, limits: newBridgeLimits(l, o)}
 }
 
+// bridge implements grpclog.DepthLoggerV2
 type bridge struct {
-	zerolog.Logger
+	logger zerolog.Logger
+	opts   *bridgeOptions
+	limits *bridgeLimits
+}
+
+// callerSkip is the count of frames between the caller of grpclog function and the zerolog event:
+// bridge.log, the bridge method and the grpclog function
+const callerSkip = 3
+
+var (
+	componentRegexp = regexp.MustCompile(`^\[([\w-]+)\] ?`)
+	channelRegexp   = regexp.MustCompile(`\b(Sub[Cc]hannel|Channel) #(\d+)`)
+)
+
+func (b *bridge) log(depth int, level zerolog.Level, msg string) {
+	if b.opts.trimNewlines {
+		msg = strings.TrimRight(msg, "\r\n")
+	}
+	if b.opts.levelFunc != nil && level < zerolog.FatalLevel {
+		level = b.opts.levelFunc(level)
+	}
+	component := ""
+	if m := componentRegexp.FindStringSubmatch(msg); m != nil {
+		component = m[1]
+		msg = msg[len(m[0]):]
+	}
+	if l, ok := b.opts.componentLevels[component]; ok && level < l && level < zerolog.FatalLevel {
+		return
+	}
+	if b.opts.filter != nil && level < zerolog.FatalLevel && !b.opts.filter(level, component, msg) {
+		return
+	}
+	if !b.limits.allow(level, component, msg) {
+		return
+	}
+
+	var e *zerolog.Event
+	switch {
+	case level == zerolog.FatalLevel && b.opts.onFatal != nil:
+		e = b.logger.WithLevel(b.opts.fatalLevel)
+		defer b.opts.onFatal(msg)
+	case level == zerolog.FatalLevel:
+		e = b.logger.Fatal()
+	default:
+		e = b.logger.WithLevel(level)
+	}
+	if component != "" {
+		e = e.Str("grpc.component", component)
+	}
+	for _, m := range channelRegexp.FindAllStringSubmatch(msg, -1) {
+		if id, err := strconv.ParseInt(m[2], 10, 64); err == nil {
+			if m[1] == "Channel" {
+				e = e.Int64("grpc.channel_id", id)
+			} else {
+				e = e.Int64("grpc.subchannel_id", id)
+			}
+		}
+	}
+	if b.opts.caller {
+		e = e.Caller(callerSkip + depth)
+	}
+	e.Msg(msg)
 }
 
 func (b *bridge) Info(args ...interface{}) {
-	b.Logger.Info().Msg(fmt.Sprint(args...))
+	b.log(0, zerolog.InfoLevel, fmt.Sprint(args...))
 }
 
 func (b *bridge) Infoln(args ...interface{}) {
-	b.Logger.Info().Msg(fmt.Sprint(args...) + "\n")
+	b.log(0, zerolog.InfoLevel, fmt.Sprintln(args...))
 }
 
 func (b *bridge) Infof(format string, args ...interface{}) {
-	b.Logger.Info().Msgf(format, args...)
+	b.log(0, zerolog.InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func (b *bridge) InfoDepth(depth int, args ...interface{}) {
+	b.log(depth, zerolog.InfoLevel, sprintln(args...))
 }
 
 func (b *bridge) Warning(args ...interface{}) {
-	b.Logger.Warn().Msg(fmt.Sprint(args...))
+	b.log(0, zerolog.WarnLevel, fmt.Sprint(args...))
 }
 
 func (b *bridge) Warningln(args ...interface{}) {
-	b.Logger.Warn().Msg(fmt.Sprint(args...) + "\n")
+	b.log(0, zerolog.WarnLevel, fmt.Sprintln(args...))
 }
 
 func (b *bridge) Warningf(format string, args ...interface{}) {
-	b.Logger.Warn().Msgf(format, args...)
+	b.log(0, zerolog.WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (b *bridge) WarningDepth(depth int, args ...interface{}) {
+	b.log(depth, zerolog.WarnLevel, sprintln(args...))
 }
 
 func (b *bridge) Error(args ...interface{}) {
-	b.Logger.Error().Msg(fmt.Sprint(args...))
+	b.log(0, zerolog.ErrorLevel, fmt.Sprint(args...))
 }
 
 func (b *bridge) Errorln(args ...interface{}) {
-	b.Logger.Error().Msg(fmt.Sprint(args...) + "\n")
+	b.log(0, zerolog.ErrorLevel, fmt.Sprintln(args...))
 }
 
 func (b *bridge) Errorf(format string, args ...interface{}) {
-	b.Logger.Error().Msgf(format, args...)
+	b.log(0, zerolog.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+func (b *bridge) ErrorDepth(depth int, args ...interface{}) {
+	b.log(depth, zerolog.ErrorLevel, sprintln(args...))
 }
 
 func (b *bridge) Fatal(args ...interface{}) {
-	b.Logger.Fatal().Msg(fmt.Sprint(args...))
+	b.log(0, zerolog.FatalLevel, fmt.Sprint(args...))
 }
 
 func (b *bridge) Fatalln(args ...interface{}) {
-	b.Logger.Fatal().Msg(fmt.Sprint(args...) + "\n")
+	b.log(0, zerolog.FatalLevel, fmt.Sprintln(args...))
 }
 
 func (b *bridge) Fatalf(format string, args ...interface{}) {
-	b.Logger.Fatal().Msgf(format, args...)
+	b.log(0, zerolog.FatalLevel, fmt.Sprintf(format, args...))
+}
+
+func (b *bridge) FatalDepth(depth int, args ...interface{}) {
+	b.log(depth, zerolog.FatalLevel, sprintln(args...))
 }
 
+// sprintln formats the args in the manner of fmt.Println without the trailing newline,
+// the depth functions are called by gRPC components with the component prefix as the first argument
+func sprintln(args ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// V reports whether the verbosity level is enabled, same as grpclog the higher verbosity means more verbose logs.
+// The level of zerolog logger doesn't affect the verbosity.
 func (b *bridge) V(verbosity int) bool {
-	// verbosity values:
-	// 0 = info
-	// 1 = warning
-	// 2 = error
-	// 3 = fatal
-	switch b.GetLevel() {
-	case zerolog.PanicLevel:
-		return verbosity > 3
-	case zerolog.FatalLevel:
-		return verbosity == 3
-	case zerolog.ErrorLevel:
-		return verbosity == 2
-	case zerolog.WarnLevel:
-		return verbosity == 1
-	case zerolog.InfoLevel:
-		return verbosity == 0
-	case zerolog.DebugLevel:
-		return true
-	case zerolog.TraceLevel:
-		return true
-	default:
-		return false
-	}
+	return verbosity <= b.opts.verbosity
 }
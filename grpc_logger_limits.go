@@ -0,0 +1,177 @@
+package grpc_zerolog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// bridgeLimits deduplicates and rate limits the gRPC log messages
+type bridgeLimits struct {
+	logger zerolog.Logger
+	window time.Duration
+	rates  map[zerolog.Level]rateLimit
+
+	mu        sync.Mutex
+	seen      map[dedupKey]*dedupEntry
+	lastSweep time.Time
+	buckets   map[zerolog.Level]*rateBucket
+}
+
+type rateLimit struct {
+	n   int
+	per time.Duration
+}
+
+type dedupKey struct {
+	level     zerolog.Level
+	component string
+	msg       string
+}
+
+type dedupEntry struct {
+	start    time.Time
+	repeated int
+	timer    *time.Timer
+}
+
+type rateBucket struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+func newBridgeLimits(logger zerolog.Logger, o *bridgeOptions) *bridgeLimits {
+	if o.dedupWindow <= 0 && len(o.rateLimits) == 0 {
+		return nil
+	}
+	return &bridgeLimits{
+		logger:  logger,
+		window:  o.dedupWindow,
+		rates:   o.rateLimits,
+		seen:    make(map[dedupKey]*dedupEntry),
+		buckets: make(map[zerolog.Level]*rateBucket),
+	}
+}
+
+// allow returns true if the message should be logged, fatal messages are always allowed
+func (l *bridgeLimits) allow(level zerolog.Level, component, msg string) bool {
+	if l == nil || level >= zerolog.FatalLevel {
+		return true
+	}
+	now := time.Now()
+	return l.dedup(dedupKey{level: level, component: component, msg: msg}, now) && l.rate(level, now)
+}
+
+func (l *bridgeLimits) dedup(k dedupKey, now time.Time) bool {
+	if l.window <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= l.window {
+		for key, e := range l.seen {
+			if e.timer == nil && now.Sub(e.start) >= l.window {
+				delete(l.seen, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	e, ok := l.seen[k]
+	if !ok || now.Sub(e.start) >= l.window && e.timer == nil {
+		l.seen[k] = &dedupEntry{start: now}
+		return true
+	}
+	e.repeated++
+	if e.timer == nil {
+		e.timer = time.AfterFunc(e.start.Add(l.window).Sub(now), func() {
+			l.flushDedup(k)
+		})
+	}
+	return false
+}
+
+func (l *bridgeLimits) flushDedup(k dedupKey) {
+	l.mu.Lock()
+	e, ok := l.seen[k]
+	if ok {
+		delete(l.seen, k)
+	}
+	l.mu.Unlock()
+
+	if ok && e.repeated > 0 {
+		l.logRepeated(k, e.repeated)
+	}
+}
+
+func (l *bridgeLimits) rate(level zerolog.Level, now time.Time) bool {
+	r, ok := l.rates[level]
+	if !ok {
+		return true
+	}
+	l.mu.Lock()
+	b, ok := l.buckets[level]
+	if !ok {
+		b = &rateBucket{start: now}
+		l.buckets[level] = b
+	}
+	suppressed := 0
+	if now.Sub(b.start) >= r.per {
+		suppressed = b.suppressed
+		b.start, b.count, b.suppressed = now, 0, 0
+	}
+	allowed := b.count < r.n
+	if allowed {
+		b.count++
+	} else {
+		b.suppressed++
+	}
+	l.mu.Unlock()
+
+	if suppressed > 0 {
+		l.logSuppressed(level, suppressed)
+	}
+	return allowed
+}
+
+// flush logs the counts of all pending duplicates and suppressed messages
+func (l *bridgeLimits) flush() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	seen, buckets := l.seen, l.buckets
+	l.seen, l.buckets = make(map[dedupKey]*dedupEntry), make(map[zerolog.Level]*rateBucket)
+	l.mu.Unlock()
+
+	for k, e := range seen {
+		if e.timer != nil {
+			e.timer.Stop()
+		}
+		if e.repeated > 0 {
+			l.logRepeated(k, e.repeated)
+		}
+	}
+	for level, b := range buckets {
+		if b.suppressed > 0 {
+			l.logSuppressed(level, b.suppressed)
+		}
+	}
+}
+
+func (l *bridgeLimits) logRepeated(k dedupKey, repeated int) {
+	e := l.logger.WithLevel(k.level)
+	if k.component != "" {
+		e = e.Str("grpc.component", k.component)
+	}
+	e.Int("grpc.repeated", repeated).Msg(k.msg)
+}
+
+func (l *bridgeLimits) logSuppressed(level zerolog.Level, suppressed int) {
+	l.logger.WithLevel(level).
+		Int("grpc.suppressed", suppressed).
+		Msgf("suppressed %d gRPC log messages", suppressed)
+}
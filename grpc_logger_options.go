@@ -0,0 +1,125 @@
+package grpc_zerolog
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// BridgeOption used to configure the gRPC logger set by ReplaceGrpcLogger
+type BridgeOption func(*bridgeOptions)
+
+// WithBridgeVerbosity sets the verbosity level of gRPC logger, the gRPC internals log verbose messages if V(level) is enabled.
+// The default is the value of GRPC_GO_LOG_VERBOSITY_LEVEL environment variable, or 0 if not set.
+func WithBridgeVerbosity(v int) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.verbosity = v
+	}
+}
+
+// WithBridgeCaller adds the caller field pointing to the gRPC source line of log statement.
+// Use it instead of zerolog.Context.Caller, which always points to the bridge.
+func WithBridgeCaller() BridgeOption {
+	return func(o *bridgeOptions) {
+		o.caller = true
+	}
+}
+
+// WithBridgeComponentLevel sets the minimal level of messages of gRPC component like "core", "transport", "balancer" or "xds".
+// The component is parsed from the message prefix and logged as grpc.component field, fatal messages are never muted.
+func WithBridgeComponentLevel(component string, level zerolog.Level) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.componentLevels[component] = level
+	}
+}
+
+// WithBridgeFatalHandler logs the gRPC fatal messages with the level instead of zerolog Fatal, which exits immediately, and calls f.
+// Note that gRPC exits the process anyway once f returns, so f is the place to run the shutdown hooks.
+func WithBridgeFatalHandler(level zerolog.Level, f func(msg string)) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.fatalLevel = level
+		o.onFatal = f
+	}
+}
+
+// WithBridgeDedup suppresses the repeated gRPC messages with the same level, component and text during the window.
+// The first message is logged as is, the count of repeats is logged as grpc.repeated field with the message once the window ends.
+func WithBridgeDedup(window time.Duration) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.dedupWindow = window
+	}
+}
+
+// WithBridgeRateLimit limits the gRPC messages of the level to n per period.
+// The count of suppressed messages is logged as grpc.suppressed field once the next period begins.
+func WithBridgeRateLimit(level zerolog.Level, n int, per time.Duration) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.rateLimits[level] = rateLimit{n: n, per: per}
+	}
+}
+
+// BridgeLevelFunc maps the level of gRPC message to the level of zerolog statement
+type BridgeLevelFunc func(level zerolog.Level) zerolog.Level
+
+// BridgeFilter decides if the gRPC message should be logged, the component is empty if the message has no component prefix
+type BridgeFilter func(level zerolog.Level, component, msg string) bool
+
+// WithBridgeLevels remaps the levels of gRPC messages, the fatal messages are not remapped, see WithBridgeFatalHandler
+func WithBridgeLevels(f BridgeLevelFunc) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.levelFunc = f
+	}
+}
+
+// WithBridgeFields adds the static fields to all gRPC messages
+func WithBridgeFields(fields map[string]interface{}) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.fields = fields
+	}
+}
+
+// WithBridgeFilter sets the filter of gRPC messages, it's called with the remapped level.
+// The fatal messages are never filtered, so WithBridgeFatalHandler runs before gRPC exits.
+func WithBridgeFilter(f BridgeFilter) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.filter = f
+	}
+}
+
+// WithBridgeTrimNewlines sets if the trailing newlines of gRPC messages are trimmed, it's enabled by default
+func WithBridgeTrimNewlines(trim bool) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.trimNewlines = trim
+	}
+}
+
+type bridgeOptions struct {
+	verbosity       int
+	caller          bool
+	componentLevels map[string]zerolog.Level
+	fatalLevel      zerolog.Level
+	onFatal         func(msg string)
+	dedupWindow     time.Duration
+	rateLimits      map[zerolog.Level]rateLimit
+	levelFunc       BridgeLevelFunc
+	fields          map[string]interface{}
+	filter          BridgeFilter
+	trimNewlines    bool
+}
+
+func evaluateBridgeOptions(opts []BridgeOption) *bridgeOptions {
+	o := &bridgeOptions{
+		componentLevels: make(map[string]zerolog.Level),
+		rateLimits:      make(map[zerolog.Level]rateLimit),
+		trimNewlines:    true,
+	}
+	if v, err := strconv.Atoi(os.Getenv("GRPC_GO_LOG_VERBOSITY_LEVEL")); err == nil {
+		o.verbosity = v
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
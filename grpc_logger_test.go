@@ -0,0 +1,255 @@
+package grpc_zerolog_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/grpclog"
+)
+
+const isolatedEnv = "GRPC_ZEROLOG_TEST_ISOLATED"
+
+// runIsolated reruns the test in a new process and returns true in the parent process, the test returns then.
+// ReplaceGrpcLogger is not synchronized with the gRPC logging, so the bridge tests run in a process free of gRPC goroutines.
+func runIsolated(t *testing.T) bool {
+	t.Helper()
+	if os.Getenv(isolatedEnv) != "" {
+		return false
+	}
+	if out, err := isolatedCommand(t).CombinedOutput(); err != nil {
+		t.Fatalf("isolated test failed: %v\n%s", err, out)
+	}
+	return true
+}
+
+func isolatedCommand(t *testing.T) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), isolatedEnv+"=1")
+	return cmd
+}
+
+// replaceGrpcLogger sets the bridge writing into the recorder until the test finishes
+func replaceGrpcLogger(t *testing.T, opts ...grpc_zerolog.BridgeOption) *grpczerologtest.Recorder {
+	t.Helper()
+	r := grpczerologtest.NewRecorder()
+	restore := grpc_zerolog.ReplaceGrpcLogger(r.Logger(), opts...)
+	t.Cleanup(restore)
+	return r
+}
+
+func messages(r *grpczerologtest.Recorder) []string {
+	var ret []string
+	for _, e := range r.Entries() {
+		ret = append(ret, e.Message)
+	}
+	return ret
+}
+
+func TestBridgeVerbosity(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	replaceGrpcLogger(t, grpc_zerolog.WithBridgeVerbosity(2))
+	for v, want := range map[int]bool{0: true, 2: true, 3: false} {
+		if got := grpclog.V(v); got != want {
+			t.Errorf("V(%d) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestBridgeComponentAndChannel(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	r := replaceGrpcLogger(t)
+	grpclog.Component("core").Infof("Channel #3 SubChannel #5: connectivity changed")
+	grpclog.Info("no component")
+
+	entries := r.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2:\n%s", len(entries), r)
+	}
+	e := entries[0]
+	if e.Message != "Channel #3 SubChannel #5: connectivity changed" || e.Fields["grpc.component"] != "core" ||
+		e.Fields["grpc.channel_id"] != 3.0 || e.Fields["grpc.subchannel_id"] != 5.0 {
+		t.Errorf("component and channels not parsed: %v", e.Fields)
+	}
+	if _, ok := entries[1].Fields["grpc.component"]; ok || entries[1].Message != "no component" {
+		t.Errorf("component logged for the message without prefix: %v", entries[1].Fields)
+	}
+}
+
+func TestBridgeComponentLevel(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	r := replaceGrpcLogger(t, grpc_zerolog.WithBridgeComponentLevel("transport", zerolog.WarnLevel))
+	grpclog.Component("transport").Info("muted")
+	grpclog.Component("transport").Warning("kept")
+	grpclog.Component("core").Info("other component")
+
+	got := messages(r)
+	if len(got) != 2 || got[0] != "kept" || got[1] != "other component" {
+		t.Errorf("got %q, want kept and other component", got)
+	}
+}
+
+func TestBridgeDedup(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	r := replaceGrpcLogger(t, grpc_zerolog.WithBridgeDedup(20*time.Millisecond))
+	for i := 0; i < 3; i++ {
+		grpclog.Warning("repeated")
+	}
+	if got := messages(r); len(got) != 1 {
+		t.Fatalf("got %q, want the first message only", got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if entries := r.Entries(); len(entries) == 2 {
+			if n := entries[1].Fields["grpc.repeated"]; n != 2.0 || entries[1].Message != "repeated" {
+				t.Errorf("got %v repeats of %q, want 2", n, entries[1].Message)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("repeats not logged at the window end:\n%s", r)
+}
+
+func TestBridgeDedupFlushedOnRestore(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	r := grpczerologtest.NewRecorder()
+	restore := grpc_zerolog.ReplaceGrpcLogger(r.Logger(), grpc_zerolog.WithBridgeDedup(time.Hour))
+	for i := 0; i < 3; i++ {
+		grpclog.Warning("repeated")
+	}
+	restore()
+
+	entries := r.Entries()
+	if len(entries) != 2 || entries[1].Fields["grpc.repeated"] != 2.0 {
+		t.Errorf("repeats not flushed by restore:\n%s", r)
+	}
+}
+
+func TestBridgeRateLimit(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	r := replaceGrpcLogger(t, grpc_zerolog.WithBridgeRateLimit(zerolog.WarnLevel, 2, 20*time.Millisecond))
+	for i := 0; i < 5; i++ {
+		grpclog.Warningf("warning %d", i)
+	}
+	grpclog.Error("other level")
+	time.Sleep(25 * time.Millisecond)
+	grpclog.Warning("next period")
+
+	entries := r.Entries()
+	got := messages(r)
+	if len(got) != 5 || got[0] != "warning 0" || got[1] != "warning 1" || got[2] != "other level" || got[4] != "next period" {
+		t.Fatalf("got %q", got)
+	}
+	if n := entries[3].Fields["grpc.suppressed"]; n != 3.0 {
+		t.Errorf("got %v suppressed, want 3", n)
+	}
+}
+
+func TestBridgeTrimNewlines(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	r := replaceGrpcLogger(t)
+	grpclog.Infoln("trimmed")
+	if got := messages(r); len(got) != 1 || got[0] != "trimmed" {
+		t.Errorf("got %q, want trimmed", got)
+	}
+
+	r = replaceGrpcLogger(t, grpc_zerolog.WithBridgeTrimNewlines(false))
+	grpclog.Infoln("kept")
+	if got := messages(r); len(got) != 1 || got[0] != "kept\n" {
+		t.Errorf("got %q, want the trailing newline kept", got)
+	}
+}
+
+func TestBridgeCaller(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	r := replaceGrpcLogger(t, grpc_zerolog.WithBridgeCaller())
+	_, file, line, _ := runtime.Caller(0)
+	grpclog.Infof("direct")
+	grpclog.Component("core").Info("component")
+
+	want := []string{
+		filepath.Base(file) + ":" + strconv.Itoa(line+1),
+		filepath.Base(file) + ":" + strconv.Itoa(line+2),
+	}
+	entries := r.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2:\n%s", len(entries), r)
+	}
+	for i, e := range entries {
+		caller, _ := e.Fields[zerolog.CallerFieldName].(string)
+		if filepath.Base(caller) != want[i] {
+			t.Errorf("%s: got caller %s, want %s", e.Message, caller, want[i])
+		}
+	}
+}
+
+func TestBridgeFatalNotFiltered(t *testing.T) {
+	if os.Getenv(isolatedEnv) == "" {
+		// gRPC exits after the fatal message, so the process is expected to fail with the handler output
+		out, err := isolatedCommand(t).CombinedOutput()
+		if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 1 {
+			t.Fatalf("got %v, want exit code 1\n%s", err, out)
+		}
+		if !strings.Contains(string(out), "fatal handled: boom") || !strings.Contains(string(out), `"message":"boom"`) {
+			t.Errorf("fatal message filtered:\n%s", out)
+		}
+		return
+	}
+
+	grpc_zerolog.ReplaceGrpcLogger(zerolog.New(os.Stdout),
+		grpc_zerolog.WithBridgeFilter(func(_ zerolog.Level, component, _ string) bool { return component != "transport" }),
+		grpc_zerolog.WithBridgeFatalHandler(zerolog.ErrorLevel, func(msg string) { fmt.Println("fatal handled:", msg) }),
+	)
+	grpclog.Component("transport").Fatal("boom")
+}
+
+func TestSuiteGrpcLogger(t *testing.T) {
+	if runIsolated(t) {
+		return
+	}
+	server, client := grpczerologtest.NewRecorder(), grpczerologtest.NewRecorder()
+	var restore func()
+	opt := grpc_zerolog.WithGrpcLogger(&restore)
+	grpc_zerolog.ServerOptions(server.Logger(), opt)
+	grpc_zerolog.DialOptions(client.Logger(), opt)
+	if restore == nil {
+		t.Fatal("restore not set")
+	}
+	grpclog.Warning("replaced")
+	restore()
+	grpclog.Warning("restored")
+
+	if got := messages(server); len(got) != 1 || got[0] != "replaced" {
+		t.Errorf("server logger got %q, want [replaced]", got)
+	}
+	if got := messages(client); len(got) != 0 {
+		t.Errorf("client logger got %q, the logger is replaced twice", got)
+	}
+}
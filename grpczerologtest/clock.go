@@ -0,0 +1,42 @@
+package grpczerologtest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the fake grpc_zerolog.Clock making the logged durations deterministic.
+// Every Now call advances the time by the step, so a call started and finished without Advance lasts exactly one step.
+type Clock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// NewClock returns the Clock starting at start
+func NewClock(start time.Time, step time.Duration) *Clock {
+	return &Clock{now: start, step: step}
+}
+
+// Now returns the current fake time and advances it by the step
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// Since returns the fake time elapsed since t
+func (c *Clock) Since(t time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now.Sub(t)
+}
+
+// Advance moves the fake time forward by d
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,46 @@
+package grpczerologtest_test
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGoldenUnaryCalls(t *testing.T) {
+	clock := grpczerologtest.NewClock(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), 10*time.Millisecond)
+	h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithoutPayload(), grpc_zerolog.WithLogOptions(grpc_zerolog.WithClock(clock)))
+	if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"}); err == nil {
+		t.Fatal("unknown service served")
+	}
+
+	assertGolden(t, "unary_calls.golden", "# server\n"+h.ServerLogs.String()+"# client\n"+h.ClientLogs.String())
+}
+
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("logs differ from %s, run with -update if the change is intended\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,74 @@
+package grpczerologtest
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/pereslava/grpc_zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1024 * 1024
+
+// Harness runs the in-memory gRPC server and client with all grpc_zerolog interceptors installed by
+// grpc_zerolog.ServerOptions and grpc_zerolog.DialOptions. The server serves the standard health service
+// and the services registered by the caller. It's stopped when the test finishes.
+type Harness struct {
+	// Server is the gRPC server, its logs are captured by ServerLogs
+	Server *grpc.Server
+	// Conn is the client connection to the server, its logs are captured by ClientLogs
+	Conn *grpc.ClientConn
+	// Health is the health service implementation, use it to set the serving status
+	Health *health.Server
+
+	ServerLogs *Recorder
+	ClientLogs *Recorder
+}
+
+// NewHarness starts the server, register may be nil or register more services on the server.
+// The same options apply to the server and the client, the recorders decode the field names set by the options.
+func NewHarness(t testing.TB, register func(s *grpc.Server), opts ...grpc_zerolog.SuiteOption) *Harness {
+	t.Helper()
+	h := &Harness{
+		Health:     health.NewServer(),
+		ServerLogs: NewRecorderWithFieldNames(grpc_zerolog.SuiteFieldNames(opts...)),
+		ClientLogs: NewRecorderWithFieldNames(grpc_zerolog.SuiteFieldNames(opts...)),
+	}
+
+	lis := bufconn.Listen(bufSize)
+	h.Server = grpc.NewServer(grpc_zerolog.ServerOptions(h.ServerLogs.Logger(), opts...)...)
+	healthpb.RegisterHealthServer(h.Server, h.Health)
+	if register != nil {
+		register(h.Server)
+	}
+	go h.Server.Serve(lis)
+
+	dialOpts := append(
+		grpc_zerolog.DialOptions(h.ClientLogs.Logger(), opts...),
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+	)
+	conn, err := grpc.Dial("bufconn", dialOpts...)
+	if err != nil {
+		h.Server.Stop()
+		t.Fatalf("cannot dial the harness server: %v", err)
+	}
+	h.Conn = conn
+
+	t.Cleanup(func() {
+		h.Conn.Close()
+		h.Server.Stop()
+	})
+	return h
+}
+
+// HealthClient returns the client of health service
+func (h *Harness) HealthClient() healthpb.HealthClient {
+	return healthpb.NewHealthClient(h.Conn)
+}
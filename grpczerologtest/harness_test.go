@@ -0,0 +1,115 @@
+package grpczerologtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const checkMethod = "/grpc.health.v1.Health/Check"
+
+func TestHarnessCallLogged(t *testing.T) {
+	h := grpczerologtest.NewHarness(t, nil)
+	if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"}); err == nil {
+		t.Fatal("unknown service served")
+	}
+
+	for _, r := range []*grpczerologtest.Recorder{h.ServerLogs, h.ClientLogs} {
+		r.AssertCallLogged(t, checkMethod, codes.OK)
+		r.AssertCallLogged(t, checkMethod, codes.NotFound)
+		r.AssertPayloadLogged(t, checkMethod)
+	}
+}
+
+func TestHarnessNoPayloadLogged(t *testing.T) {
+	h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithoutPayload())
+	if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []*grpczerologtest.Recorder{h.ServerLogs, h.ClientLogs} {
+		r.AssertCallLogged(t, checkMethod, codes.OK)
+		r.AssertNoPayloadLogged(t, checkMethod)
+	}
+}
+
+func TestHarnessFieldNames(t *testing.T) {
+	for name, names := range map[string]grpc_zerolog.FieldNames{
+		"ecs":  grpc_zerolog.ECSFieldNames,
+		"otel": grpc_zerolog.OTelFieldNames,
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithLogOptions(grpc_zerolog.WithFieldNames(names)))
+			if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+				t.Fatal(err)
+			}
+
+			h.ServerLogs.AssertCallLogged(t, checkMethod, codes.OK)
+			h.ClientLogs.AssertCallLogged(t, checkMethod, codes.OK)
+			for _, e := range h.ServerLogs.CallEntries(checkMethod) {
+				if _, ok := e.Fields["grpc.code"]; ok {
+					t.Errorf("default code field logged with %s names: %v", name, e.Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestHarnessDetailFieldNames(t *testing.T) {
+	h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithLogOptions(grpc_zerolog.WithFieldNames(grpc_zerolog.OTelFieldNames)))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := h.HealthClient().Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	h.ClientLogs.AssertCallLogged(t, checkMethod, codes.OK)
+	for _, e := range h.ClientLogs.CallEntries(checkMethod) {
+		if !e.HasCode {
+			continue
+		}
+		for _, name := range []string{"rpc.grpc.compression", "rpc.grpc.codec", "rpc.grpc.deadline.budget"} {
+			if _, ok := e.Fields[name]; !ok {
+				t.Errorf("%s not logged: %v", name, e.Fields)
+			}
+		}
+		for _, name := range []string{"grpc.compression", "grpc.codec", "grpc.deadline.budget"} {
+			if _, ok := e.Fields[name]; ok {
+				t.Errorf("default %s logged with otel names: %v", name, e.Fields)
+			}
+		}
+	}
+}
+
+func TestHarnessPayloadFieldNames(t *testing.T) {
+	h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithLogOptions(grpc_zerolog.WithFieldNames(grpc_zerolog.OTelFieldNames)))
+	if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []*grpczerologtest.Recorder{h.ServerLogs, h.ClientLogs} {
+		r.AssertPayloadLogged(t, checkMethod)
+		for _, e := range r.Entries() {
+			if _, ok := e.Fields["grpc.method"]; ok {
+				t.Errorf("default method field logged with otel names: %v", e.Fields)
+			}
+		}
+	}
+}
+
+func TestHarnessCallNotLogged(t *testing.T) {
+	h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithLogOptions(grpc_zerolog.WithDecider(grpc_zerolog.Not(grpc_zerolog.HealthMatcher).Decider())))
+	if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	h.ServerLogs.AssertCallNotLogged(t, checkMethod)
+	h.ClientLogs.AssertCallNotLogged(t, checkMethod)
+}
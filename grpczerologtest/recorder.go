@@ -0,0 +1,194 @@
+// grpczerologtest provides helpers for testing the log output of grpc_zerolog interceptors
+package grpczerologtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+)
+
+// Entry is the decoded log statement
+type Entry struct {
+	Level   zerolog.Level
+	Message string
+	// Method is the full method name like "/package.Service/Method", empty if the statement is not related to a call
+	Method string
+	// Code is the gRPC code of finished call, HasCode is false if the statement has no code
+	Code    codes.Code
+	HasCode bool
+	// Duration is the duration of finished call
+	Duration time.Duration
+	// Fields contains all fields of statement, including the decoded above
+	Fields map[string]interface{}
+}
+
+// Recorder captures the output of zerolog logger, it's safe for concurrent use
+type Recorder struct {
+	names grpc_zerolog.FieldNames
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewRecorder returns an empty Recorder decoding the statements logged with grpc_zerolog.DefaultFieldNames
+func NewRecorder() *Recorder {
+	return NewRecorderWithFieldNames(grpc_zerolog.DefaultFieldNames)
+}
+
+// NewRecorderWithFieldNames returns an empty Recorder decoding the statements logged with the field names
+func NewRecorderWithFieldNames(names grpc_zerolog.FieldNames) *Recorder {
+	return &Recorder{names: names}
+}
+
+// Write implements io.Writer
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.Write(p)
+}
+
+// Logger returns the logger of Trace level writing into the recorder
+func (r *Recorder) Logger() zerolog.Logger {
+	return zerolog.New(r).Level(zerolog.TraceLevel)
+}
+
+// Reset drops all captured statements
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf.Reset()
+}
+
+// Entries decodes all captured statements, the lines that are not JSON objects are skipped
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	data := append([]byte(nil), r.buf.Bytes()...)
+	r.mu.Unlock()
+
+	var entries []Entry
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(nil, len(data)+1)
+	for s.Scan() {
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(s.Bytes(), &fields); err != nil {
+			continue
+		}
+		entries = append(entries, decode(fields, &r.names))
+	}
+	return entries
+}
+
+// CallEntries returns the decoded statements of the method
+func (r *Recorder) CallEntries(fullMethodName string) []Entry {
+	var ret []Entry
+	for _, e := range r.Entries() {
+		if e.Method == fullMethodName {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// AssertCallLogged fails the test if there is no finished call statement of the method with the code
+func (r *Recorder) AssertCallLogged(t testing.TB, fullMethodName string, code codes.Code) {
+	t.Helper()
+	for _, e := range r.CallEntries(fullMethodName) {
+		if e.HasCode && e.Code == code {
+			return
+		}
+	}
+	t.Errorf("no call of %s with code %s logged, captured:\n%s", fullMethodName, code, r.String())
+}
+
+// AssertCallNotLogged fails the test if there is any finished call statement of the method
+func (r *Recorder) AssertCallNotLogged(t testing.TB, fullMethodName string) {
+	t.Helper()
+	for _, e := range r.CallEntries(fullMethodName) {
+		if e.HasCode {
+			t.Errorf("call of %s logged with code %s, captured:\n%s", fullMethodName, e.Code, r.String())
+			return
+		}
+	}
+}
+
+// AssertPayloadLogged fails the test if there is no request or response payload statement of the method
+func (r *Recorder) AssertPayloadLogged(t testing.TB, fullMethodName string) {
+	t.Helper()
+	for _, e := range r.CallEntries(fullMethodName) {
+		if isPayload(e) {
+			return
+		}
+	}
+	t.Errorf("no payload of %s logged, captured:\n%s", fullMethodName, r.String())
+}
+
+// AssertNoPayloadLogged fails the test if there is any request or response payload statement of the method
+func (r *Recorder) AssertNoPayloadLogged(t testing.TB, fullMethodName string) {
+	t.Helper()
+	for _, e := range r.CallEntries(fullMethodName) {
+		if isPayload(e) {
+			t.Errorf("payload of %s logged, captured:\n%s", fullMethodName, r.String())
+			return
+		}
+	}
+}
+
+// String returns the captured statements as written, e.g. to compare them with a golden file
+func (r *Recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.String()
+}
+
+func isPayload(e Entry) bool {
+	_, req := e.Fields["grpc.request.payload"]
+	_, res := e.Fields["grpc.response.payload"]
+	return req || res
+}
+
+var codesByName = func() map[string]codes.Code {
+	m := make(map[string]codes.Code)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		m[c.String()] = c
+	}
+	return m
+}()
+
+func decode(fields map[string]interface{}, names *grpc_zerolog.FieldNames) Entry {
+	e := Entry{Level: zerolog.NoLevel, Fields: fields}
+	if s, ok := fields[zerolog.LevelFieldName].(string); ok {
+		if l, err := zerolog.ParseLevel(s); err == nil {
+			e.Level = l
+		}
+	}
+	e.Message, _ = fields[zerolog.MessageFieldName].(string)
+	service, _ := fields[names.Service].(string)
+	method, _ := fields[names.Method].(string)
+	if service != "" || method != "" {
+		e.Method = "/" + service + "/" + method
+	}
+	switch c := fields[names.Code].(type) {
+	case string:
+		e.Code, e.HasCode = codesByName[c]
+	case float64:
+		e.Code, e.HasCode = codes.Code(c), names.NumericCode
+	}
+	switch d := fields[names.Duration].(type) {
+	case float64:
+		if names.DurationFormat == grpc_zerolog.DurationNanos {
+			e.Duration = time.Duration(d)
+		} else {
+			e.Duration = time.Duration(d * float64(time.Millisecond))
+		}
+	case string:
+		e.Duration, _ = time.ParseDuration(d)
+	}
+	return e
+}
@@ -0,0 +1,55 @@
+package grpc_zerolog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+)
+
+const msgInfraSummary message = "suppressed infrastructure calls"
+
+// infraSuppressor counts the successful calls of infrastructure methods instead of logging them
+type infraSuppressor struct {
+	matcher  Matcher
+	interval time.Duration
+
+	mu     sync.Mutex
+	counts map[string]int
+	logger zerolog.Logger
+	o      *options
+	timer  *time.Timer
+}
+
+// suppressed returns true if the call should not be logged, the call is counted for the summary then.
+// The suppressor belongs to one interceptor, so the logger and options are the same for all calls.
+func (s *infraSuppressor) suppressed(logger zerolog.Logger, o *options, fullMethodName string, err error) bool {
+	if s == nil || err != nil || !s.matcher(fullMethodName) {
+		return false
+	}
+	if s.interval <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[fullMethodName]++
+	s.logger, s.o = logger, o
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.interval, s.flush)
+	}
+	return true
+}
+
+func (s *infraSuppressor) flush() {
+	s.mu.Lock()
+	counts, logger, o := s.counts, s.logger, s.o
+	s.counts, s.timer = make(map[string]int), nil
+	s.mu.Unlock()
+
+	for method, n := range counts {
+		l := millisField(initLog(nil, logger, method, &o.fields).Int("grpc.calls", n), "grpc.interval_ms", s.interval).Logger()
+		l.WithLevel(o.levelFunc(codes.OK)).Msg(string(msgInfraSummary))
+	}
+}
@@ -0,0 +1,56 @@
+package grpc_zerolog_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestInfraSuppressionPerInterceptor(t *testing.T) {
+	h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithLogOptions(grpc_zerolog.WithInfraSuppression(20*time.Millisecond)))
+	for i := 0; i < 3; i++ {
+		if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, r := range map[string]*grpczerologtest.Recorder{"server": h.ServerLogs, "client": h.ClientLogs} {
+		if calls := waitInfraSummary(t, r).Fields["grpc.calls"]; calls != 3.0 {
+			t.Errorf("%s summary has %v calls, want 3", name, calls)
+		}
+	}
+}
+
+func TestInfraSummaryDurationFormat(t *testing.T) {
+	h := grpczerologtest.NewHarness(t, nil, grpc_zerolog.WithLogOptions(
+		grpc_zerolog.WithInfraSuppression(20*time.Millisecond),
+		grpc_zerolog.WithDurationFormat(grpc_zerolog.DurationString),
+	))
+	if _, err := h.HealthClient().Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the _ms fields are milliseconds whatever the duration format is
+	if interval := waitInfraSummary(t, h.ServerLogs).Fields["grpc.interval_ms"]; interval != float64(20) {
+		t.Errorf("summary has interval %v, want 20", interval)
+	}
+}
+
+func waitInfraSummary(t *testing.T, r *grpczerologtest.Recorder) grpczerologtest.Entry {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		for _, e := range r.Entries() {
+			if e.Message == "suppressed infrastructure calls" {
+				return e
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("no summary of infrastructure calls logged")
+	return grpczerologtest.Entry{}
+}
@@ -2,13 +2,14 @@ package grpc_zerolog
 
 import (
 	"context"
-	"path"
+	"strings"
 	"time"
 
 	"github.com/pereslava/grpc_zerolog/ctxzerolog"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -22,14 +23,18 @@ const (
 func NewUnaryServerInterceptor(logger zerolog.Logger, opts ...Option) grpc.UnaryServerInterceptor {
 	o := evaluateOptions(opts)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		start := time.Now()
-		l := initLog(ctx, logger, info.FullMethod)
-
-		res, err := handler(ctxzerolog.New(ctx, l.Logger()), req)
-		if !o.shouldLog(info.FullMethod, err) {
+		start, wallStart := o.clock.Now(), time.Now()
+		md := incomingMetadata(ctx)
+		l := logAttempt(logMetadata(initLog(ctx, o.loggerFor(logger, info.FullMethod), info.FullMethod, &o.fields), md, o.metadataKeys()), md)
+
+		watch := o.watchCancel(ctx)
+		res, err := handler(ctxzerolog.NewWithClock(ctx, l.Logger(), o.clock), req)
+		call := CallInfo{Context: ctx, FullMethod: info.FullMethod, Kind: CallUnary, Err: err, Start: start, Duration: o.clock.Since(start), wallStart: wallStart}
+		watch.finish(&call)
+		if !o.shouldLog(info.FullMethod, err) || o.infra.suppressed(logger, o, info.FullMethod, err) {
 			return res, err
 		}
-		doInterceptorLog(l, start, err, msgUnary, o.levelFunc)
+		o.logCall(o.fields.messageSize(o.fields.serverStream(l, ctx), err, callLimits{}), call)
 
 		return res, err
 	}
@@ -39,15 +44,21 @@ func NewUnaryServerInterceptor(logger zerolog.Logger, opts ...Option) grpc.Unary
 func NewUnaryClientInterceptor(logger zerolog.Logger, opts ...Option) grpc.UnaryClientInterceptor {
 	o := evaluateOptions(opts)
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		start := time.Now()
+		start, wallStart := o.clock.Now(), time.Now()
 
+		ctx, attempts := withAttempts(ctx, o.clock)
 		err := invoker(ctx, method, req, reply, cc, opts...)
-		if !o.shouldLog(method, err) {
+		if !o.shouldLog(method, err) || o.infra.suppressed(logger, o, method, err) {
 			return err
 		}
 
-		l := initLog(ctx, logger, method)
-		doInterceptorLog(l, start, err, msgUnary, o.levelFunc)
+		l := logMetadata(initLog(ctx, o.loggerFor(logger, method), method, &o.fields), outgoingMetadata(ctx), o.metadataKeys())
+		l, limits := o.fields.callOptions(l, opts)
+		if list := attempts.finished(err); len(list) > 0 {
+			o.logAttempts(l, ctx, method, list)
+			l = l.Int("grpc.attempts", len(list))
+		}
+		o.logCall(o.fields.messageSize(l, err, limits), CallInfo{Context: ctx, FullMethod: method, Kind: CallUnary, Client: true, Err: err, Start: start, Duration: o.clock.Since(start), wallStart: wallStart})
 
 		return err
 	}
@@ -57,18 +68,22 @@ func NewUnaryClientInterceptor(logger zerolog.Logger, opts ...Option) grpc.Unary
 func NewStreamServerInterceptor(logger zerolog.Logger, opts ...Option) grpc.StreamServerInterceptor {
 	o := evaluateOptions(opts)
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		start := time.Now()
+		start, wallStart := o.clock.Now(), time.Now()
 
 		wrapped := wrapServerStream(stream)
-		l := initLog(wrapped.wrappedContext, logger, info.FullMethod)
-		wrapped.wrappedContext = ctxzerolog.New(wrapped.wrappedContext, l.Logger())
+		md := incomingMetadata(wrapped.wrappedContext)
+		l := logAttempt(logMetadata(initLog(wrapped.wrappedContext, o.loggerFor(logger, info.FullMethod), info.FullMethod, &o.fields), md, o.metadataKeys()), md)
+		wrapped.wrappedContext = ctxzerolog.NewWithClock(wrapped.wrappedContext, l.Logger(), o.clock)
 
+		watch := o.watchCancel(wrapped.wrappedContext)
 		err := handler(srv, wrapped)
-		if !o.shouldLog(info.FullMethod, err) {
+		call := CallInfo{Context: wrapped.wrappedContext, FullMethod: info.FullMethod, Kind: CallServerStream, Err: err, Start: start, Duration: o.clock.Since(start), wallStart: wallStart}
+		watch.finish(&call)
+		if !o.shouldLog(info.FullMethod, err) || o.infra.suppressed(logger, o, info.FullMethod, err) {
 			return err
 		}
 
-		doInterceptorLog(l, start, err, msgServerStream, o.levelFunc)
+		o.logCall(o.fields.messageSize(o.fields.serverStream(l, wrapped.wrappedContext), err, callLimits{}), call)
 
 		return err
 	}
@@ -78,49 +93,81 @@ func NewStreamServerInterceptor(logger zerolog.Logger, opts ...Option) grpc.Stre
 func NewStreamClientInterceptor(logger zerolog.Logger, opts ...Option) grpc.StreamClientInterceptor {
 	o := evaluateOptions(opts)
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
-		start := time.Now()
+		start, wallStart := o.clock.Now(), time.Now()
 
 		cs, err := streamer(ctx, desc, cc, method, opts...)
-		if !o.shouldLog(method, err) {
+		if !o.shouldLog(method, err) || o.infra.suppressed(logger, o, method, err) {
 			return cs, err
 		}
 
-		l := initLog(ctx, logger, method)
-		doInterceptorLog(l, start, err, msgClientStream, o.levelFunc)
+		l, limits := o.fields.callOptions(logMetadata(initLog(ctx, o.loggerFor(logger, method), method, &o.fields), outgoingMetadata(ctx), o.metadataKeys()), opts)
+		o.logCall(o.fields.messageSize(l, err, limits), CallInfo{Context: ctx, FullMethod: method, Kind: CallClientStream, Client: true, Err: err, Start: start, Duration: o.clock.Since(start), wallStart: wallStart})
 
 		return cs, err
 	}
 }
 
-func initLog(ctx context.Context, logger zerolog.Logger, fullMethodString string) zerolog.Context {
-	service := path.Dir(fullMethodString)[1:]
-	method := path.Base(fullMethodString)
+func initLog(ctx context.Context, logger zerolog.Logger, fullMethodString string, names *FieldNames) zerolog.Context {
+	service, method := splitMethod(fullMethodString)
 
-	with := logger.With().
-		Str("grpc.service", service).
-		Str("grpc.method", method)
+	with := names.call(logger.With(), service, method)
 
 	if ctx == nil {
 		return with
 	}
 
 	if d, ok := ctx.Deadline(); ok {
-		with = with.Time("grpc.request.deadline", d)
+		with = with.Time(names.Deadline, d)
 	}
 
 	return with
 }
 
+func logMetadata(with zerolog.Context, md metadata.MD, keys []string) zerolog.Context {
+	for _, k := range keys {
+		if v := md.Get(k); len(v) > 0 {
+			with = with.Str("grpc.metadata."+strings.ToLower(k), strings.Join(v, ","))
+		}
+	}
+	return with
+}
+
+func incomingMetadata(ctx context.Context) metadata.MD {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return md
+}
+
+func outgoingMetadata(ctx context.Context) metadata.MD {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return md
+}
+
 type message string
 
-func doInterceptorLog(log zerolog.Context, start time.Time, callError error, msg message, ctl CodeToLevel) {
-	code := status.Code(callError)
-	with := log.Str("grpc.code", code.String()).Dur("grpc.time_ms", time.Since(start))
-	if callError != nil {
-		with = with.Err(callError)
+// logCall writes the call statement of kind with the code, duration and error of info
+func (o *options) logCall(log zerolog.Context, info CallInfo) {
+	with, level := log, zerolog.WarnLevel
+	if !info.Retried {
+		info.Code = status.Code(info.Err)
+		with, level = o.fields.code(with, info.Code), o.levelFunc(info.Code)
+	}
+	with = o.fields.duration(with, info.Duration)
+	if info.Context != nil {
+		with = o.fields.deadline(with, info)
+		with = o.fields.cancel(with, info)
+	}
+	if info.Err != nil {
+		with = o.fields.err(with, info.Err)
 	}
 	l := with.Logger()
-	l.WithLevel(ctl(code)).Msg(string(msg))
+	e := l.WithLevel(level)
+	if e == nil {
+		return
+	}
+	for _, h := range o.hooks {
+		h(e, info)
+	}
+	e.Msg(string(o.messages[info.Kind]))
 }
 
 type wrappedServerStream struct {
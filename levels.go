@@ -0,0 +1,221 @@
+package grpc_zerolog
+
+import (
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelRegistry keeps the log level and payload logging overrides of gRPC methods.
+// It's safe for concurrent use and can be changed at runtime, the interceptors configured by WithLevelRegistry
+// and WithPayloadRegistry use the current state on every call.
+// The levels and payload methods of a Policy using the registry are kept apart, the overrides take precedence over them.
+type LevelRegistry struct {
+	mu      sync.RWMutex
+	levels  map[string]override
+	payload map[string]override
+
+	policyLevels  map[string]override
+	policyPayload map[string]override
+}
+
+// Override describes an override of LevelRegistry
+type Override struct {
+	// Pattern is a full method name or a glob of methods
+	Pattern string
+	// Level is the overridden log level, it's zerolog.NoLevel for payload overrides
+	Level zerolog.Level
+	// Payload is true if the override enables payload logging
+	Payload bool
+	// Expires is the time when the override is removed, zero value means never
+	Expires time.Time
+}
+
+type override struct {
+	level   zerolog.Level
+	expires time.Time
+}
+
+func (o override) expired(now time.Time) bool {
+	return !o.expires.IsZero() && !now.Before(o.expires)
+}
+
+// NewLevelRegistry returns an empty LevelRegistry
+func NewLevelRegistry() *LevelRegistry {
+	return &LevelRegistry{
+		levels:  make(map[string]override),
+		payload: make(map[string]override),
+	}
+}
+
+// Set overrides the log level of methods matching the pattern.
+// The pattern is a full method name like "/payments.Payments/Charge" or a path.Match glob like "/payments.Payments/*".
+func (r *LevelRegistry) Set(pattern string, level zerolog.Level) error {
+	return r.SetFor(pattern, level, 0)
+}
+
+// SetFor overrides the log level of methods matching the pattern for ttl, zero ttl means forever
+func (r *LevelRegistry) SetFor(pattern string, level zerolog.Level, ttl time.Duration) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.levels[pattern] = override{level: level, expires: expiresAfter(ttl)}
+	return nil
+}
+
+// Unset removes the level override set by the pattern
+func (r *LevelRegistry) Unset(pattern string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.levels, pattern)
+}
+
+// EnablePayload enables the payload logging of methods matching the pattern for ttl, zero ttl means forever.
+// It takes effect even if the PayloadDecider suppresses the method.
+func (r *LevelRegistry) EnablePayload(pattern string, ttl time.Duration) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payload[pattern] = override{level: zerolog.NoLevel, expires: expiresAfter(ttl)}
+	return nil
+}
+
+// DisablePayload removes the payload override set by the pattern
+func (r *LevelRegistry) DisablePayload(pattern string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.payload, pattern)
+}
+
+// Overrides returns all not expired overrides sorted by pattern, level overrides go first, the policy levels are not included
+func (r *LevelRegistry) Overrides() []Override {
+	now := time.Now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ret := make([]Override, 0, len(r.levels)+len(r.payload))
+	for i, set := range []map[string]override{r.levels, r.payload} {
+		n := len(ret)
+		for p, o := range set {
+			if o.expired(now) {
+				continue
+			}
+			ret = append(ret, Override{Pattern: p, Level: o.level, Payload: i == 1, Expires: o.expires})
+		}
+		added := ret[n:]
+		sort.Slice(added, func(i, j int) bool { return added[i].Pattern < added[j].Pattern })
+	}
+	return ret
+}
+
+// Level returns the level override of fullMethodName.
+// The exact method name wins over globs, otherwise the longest matching glob wins, the lexically first of equally long ones.
+func (r *LevelRegistry) Level(fullMethodName string) (zerolog.Level, bool) {
+	if r == nil {
+		return zerolog.NoLevel, false
+	}
+	o, ok := r.lookup(false, fullMethodName)
+	return o.level, ok
+}
+
+// PayloadEnabled returns true if the payload logging of fullMethodName is enabled by override
+func (r *LevelRegistry) PayloadEnabled(fullMethodName string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.lookup(true, fullMethodName)
+	return ok
+}
+
+func (r *LevelRegistry) lookup(payload bool, fullMethodName string) (override, bool) {
+	now := time.Now()
+	r.mu.RLock()
+	set, policy := r.levels, r.policyLevels
+	if payload {
+		set, policy = r.payload, r.policyPayload
+	}
+	o, found, expired := match(set, fullMethodName, now)
+	if !found {
+		o, found, _ = match(policy, fullMethodName, now)
+	}
+	r.mu.RUnlock()
+
+	if expired {
+		r.removeExpired(payload, now)
+	}
+	return o, found
+}
+
+// match returns the override of the exact method name or the longest matching glob, and if there are expired overrides.
+// The lexically first of equally long globs wins, so the result does not depend on the map order.
+func match(set map[string]override, fullMethodName string, now time.Time) (override, bool, bool) {
+	if o, ok := set[fullMethodName]; ok && !o.expired(now) {
+		return o, true, false
+	}
+	found, ret, expired := "", override{level: zerolog.NoLevel}, false
+	for p, o := range set {
+		if o.expired(now) {
+			expired = true
+			continue
+		}
+		if found != "" && (len(p) < len(found) || len(p) == len(found) && p > found) {
+			continue
+		}
+		if ok, _ := path.Match(p, fullMethodName); ok {
+			found, ret = p, o
+		}
+	}
+	return ret, found != "", expired
+}
+
+func (r *LevelRegistry) removeExpired(payload bool, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	set := r.levels
+	if payload {
+		set = r.payload
+	}
+	for p, o := range set {
+		if o.expired(now) {
+			delete(set, p)
+		}
+	}
+}
+
+// apply returns the logger with level overridden for fullMethodName, or the logger as is if there is no override
+func (r *LevelRegistry) apply(logger zerolog.Logger, fullMethodName string) zerolog.Logger {
+	if r == nil {
+		return logger
+	}
+	if l, ok := r.Level(fullMethodName); ok {
+		return logger.Level(l)
+	}
+	return logger
+}
+
+func expiresAfter(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// setPolicy replaces the levels and payload patterns of the policy, the overrides are kept
+func (r *LevelRegistry) setPolicy(levels map[string]zerolog.Level, payload []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policyLevels = make(map[string]override, len(levels))
+	for p, l := range levels {
+		r.policyLevels[p] = override{level: l}
+	}
+	r.policyPayload = make(map[string]override, len(payload))
+	for _, p := range payload {
+		r.policyPayload[p] = override{level: zerolog.NoLevel}
+	}
+}
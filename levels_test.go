@@ -0,0 +1,98 @@
+package grpc_zerolog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestMatch(t *testing.T) {
+	now := time.Now()
+	set := map[string]override{
+		"/pkg.Svc/Get":  {level: zerolog.ErrorLevel},
+		"/pkg.Svc/*":    {level: zerolog.WarnLevel},
+		"/pkg.Svc/G*":   {level: zerolog.InfoLevel},
+		"/pkg.Svc/*t":   {level: zerolog.DebugLevel},
+		"/pkg.*/*":      {level: zerolog.TraceLevel},
+		"/pkg.Old/*":    {level: zerolog.PanicLevel, expires: now.Add(-time.Second)},
+		"/pkg.Later/*":  {level: zerolog.FatalLevel, expires: now.Add(time.Hour)},
+		"/pkg.Svc/Li*":  {level: zerolog.InfoLevel},
+		"/pkg.Svc/Lis*": {level: zerolog.DebugLevel},
+	}
+	for method, want := range map[string]zerolog.Level{
+		"/pkg.Svc/Get":   zerolog.ErrorLevel, // exact method wins
+		"/pkg.Svc/Set":   zerolog.DebugLevel, // longest glob wins
+		"/pkg.Svc/Gox":   zerolog.InfoLevel,
+		"/pkg.Svc/Gaet":  zerolog.DebugLevel, // equally long "/pkg.Svc/*t" is lexically before "/pkg.Svc/G*"
+		"/pkg.Svc/List":  zerolog.DebugLevel,
+		"/pkg.Other/Get": zerolog.TraceLevel,
+		"/pkg.Later/Get": zerolog.FatalLevel,
+		"/pkg.Old/Get":   zerolog.TraceLevel, // expired override skipped
+		"/other.Svc/Get": zerolog.NoLevel,
+	} {
+		for i := 0; i < 10; i++ {
+			if o, _, _ := match(set, method, now); o.level != want {
+				t.Fatalf("%s: got %v, want %v", method, o.level, want)
+			}
+		}
+	}
+	if _, _, expired := match(set, "/other.Svc/Get", now); !expired {
+		t.Errorf("expired override not reported")
+	}
+}
+
+func TestLevelRegistryExpiry(t *testing.T) {
+	r := NewLevelRegistry()
+	if err := r.SetFor("/pkg.Svc/*", zerolog.DebugLevel, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.EnablePayload("/pkg.Svc/*", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if l, ok := r.Level("/pkg.Svc/Get"); !ok || l != zerolog.DebugLevel {
+		t.Fatalf("got level %v %v, want debug", l, ok)
+	}
+
+	// expire the overrides as if the ttl passed
+	r.mu.Lock()
+	for _, set := range []map[string]override{r.levels, r.payload} {
+		o := set["/pkg.Svc/*"]
+		o.expires = time.Now().Add(-time.Second)
+		set["/pkg.Svc/*"] = o
+	}
+	r.mu.Unlock()
+
+	if _, ok := r.Level("/pkg.Svc/Get"); ok {
+		t.Errorf("expired level override applied")
+	}
+	if r.PayloadEnabled("/pkg.Svc/Get") {
+		t.Errorf("expired payload override applied")
+	}
+	if n := len(r.levels) + len(r.payload); n != 0 {
+		t.Errorf("%d expired overrides not removed", n)
+	}
+	if o := r.Overrides(); len(o) != 0 {
+		t.Errorf("expired overrides listed: %v", o)
+	}
+}
+
+func TestLevelRegistryNil(t *testing.T) {
+	var r *LevelRegistry
+	if _, ok := r.Level("/pkg.Svc/Get"); ok {
+		t.Errorf("nil registry has level")
+	}
+	if r.PayloadEnabled("/pkg.Svc/Get") {
+		t.Errorf("nil registry enables payload")
+	}
+}
+
+func TestLevelRegistryInvalidGlob(t *testing.T) {
+	r := NewLevelRegistry()
+	if err := r.Set("/pkg.Svc/[", zerolog.DebugLevel); err == nil {
+		t.Errorf("invalid glob accepted")
+	}
+	if err := r.EnablePayload("/pkg.Svc/[", 0); err == nil {
+		t.Errorf("invalid glob accepted")
+	}
+}
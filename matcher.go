@@ -0,0 +1,143 @@
+package grpc_zerolog
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	// HealthMatcher matches the methods of standard gRPC health checking service
+	HealthMatcher = MatchService("grpc.health.v1.Health")
+
+	// ReflectionMatcher matches the methods of standard gRPC server reflection services
+	ReflectionMatcher = Or(
+		MatchService("grpc.reflection.v1alpha.ServerReflection"),
+		MatchService("grpc.reflection.v1.ServerReflection"),
+	)
+
+	// ChannelzMatcher matches the methods of standard gRPC channelz service
+	ChannelzMatcher = MatchService("grpc.channelz.v1.Channelz")
+
+	// InfraMatcher matches the methods of all standard gRPC infrastructure services: health, reflection and channelz
+	InfraMatcher = Or(HealthMatcher, ReflectionMatcher, ChannelzMatcher)
+)
+
+// Matcher decides if the gRPC method matches by the full method name like "/package.Service/Method"
+type Matcher func(fullMethodName string) bool
+
+// MatchMethod matches the method by exact full method name
+func MatchMethod(fullMethodName string) Matcher {
+	return func(m string) bool {
+		return m == fullMethodName
+	}
+}
+
+// MatchService matches all methods of the service given by full name like "package.Service"
+func MatchService(service string) Matcher {
+	return func(m string) bool {
+		s, _ := splitMethod(m)
+		return s == service
+	}
+}
+
+// MatchPackage matches all methods of services in the package or its subpackages,
+// e.g. "payments" matches "payments.Payments" and "payments.v1.Refunds" services
+func MatchPackage(pkg string) Matcher {
+	return func(m string) bool {
+		s, _ := splitMethod(m)
+		i := strings.LastIndex(s, ".")
+		if i < 0 {
+			return false
+		}
+		p := s[:i]
+		return p == pkg || strings.HasPrefix(p, pkg+".")
+	}
+}
+
+// MatchGlob matches the full method name by path.Match pattern like "/payments.Payments/*", the invalid pattern matches nothing
+func MatchGlob(pattern string) Matcher {
+	return func(m string) bool {
+		ok, _ := path.Match(pattern, m)
+		return ok
+	}
+}
+
+// MatchRegexp matches the full method name by the regular expression, it panics if re is nil
+func MatchRegexp(re *regexp.Regexp) Matcher {
+	if re == nil {
+		panic("grpc_zerolog: MatchRegexp with nil regexp")
+	}
+	return re.MatchString
+}
+
+// And matches if all matchers match
+func And(matchers ...Matcher) Matcher {
+	return func(m string) bool {
+		for _, matcher := range matchers {
+			if !matcher(m) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches if any of matchers matches
+func Or(matchers ...Matcher) Matcher {
+	return func(m string) bool {
+		for _, matcher := range matchers {
+			if matcher(m) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches if the matcher doesn't match
+func Not(matcher Matcher) Matcher {
+	return func(m string) bool {
+		return !matcher(m)
+	}
+}
+
+// Decider returns the Decider that logs the matching methods only, use Not to suppress the matching methods
+func (m Matcher) Decider() Decider {
+	return func(fullMethodName string, err error) bool {
+		return m(fullMethodName)
+	}
+}
+
+// PayloadDecider returns the PayloadDecider that logs the payload of matching methods only
+func (m Matcher) PayloadDecider() PayloadDecider {
+	return PayloadDecider(m)
+}
+
+// Level returns the rule setting the log level of matching methods, see WithLevelRules
+func (m Matcher) Level(level zerolog.Level) LevelRule {
+	return LevelRule{Matcher: m, Level: level}
+}
+
+// LevelRule sets the log level of methods matching the Matcher
+type LevelRule struct {
+	Matcher Matcher
+	Level   zerolog.Level
+}
+
+// levelOf returns the level of the first matching rule
+func levelOf(rules []LevelRule, fullMethodName string) (zerolog.Level, bool) {
+	for _, r := range rules {
+		if r.Matcher(fullMethodName) {
+			return r.Level, true
+		}
+	}
+	return zerolog.NoLevel, false
+}
+
+// splitMethod splits the full method name like "/package.Service/Method" into service and method names
+func splitMethod(fullMethodName string) (string, string) {
+	return path.Dir(fullMethodName)[1:], path.Base(fullMethodName)
+}
@@ -0,0 +1,90 @@
+package grpc_zerolog
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestMatchers(t *testing.T) {
+	for name, tc := range map[string]struct {
+		matcher Matcher
+		matches []string
+		misses  []string
+	}{
+		"method": {MatchMethod("/payments.Payments/Charge"),
+			[]string{"/payments.Payments/Charge"},
+			[]string{"/payments.Payments/ChargeAll", "/payments.Refunds/Charge"}},
+		"service": {MatchService("payments.Payments"),
+			[]string{"/payments.Payments/Charge"},
+			[]string{"/payments.PaymentsV2/Charge", "/other.payments.Payments/Charge", "/payments.v1.Payments/Charge"}},
+		"package": {MatchPackage("payments"),
+			[]string{"/payments.Payments/Charge", "/payments.v1.Refunds/Get"},
+			[]string{"/paymentsx.Payments/Charge", "/payments2.v1.Refunds/Get", "/Payments/Charge", "/other.payments.Payments/Charge"}},
+		"subpackage": {MatchPackage("payments.v1"),
+			[]string{"/payments.v1.Refunds/Get"},
+			[]string{"/payments.Payments/Charge", "/payments.v10.Refunds/Get"}},
+		"glob": {MatchGlob("/payments.*/Get*"),
+			[]string{"/payments.Refunds/Get", "/payments.Payments/GetAll"},
+			[]string{"/payments.Payments/Charge"}},
+		"invalid glob": {MatchGlob("/payments.[/*"),
+			nil,
+			[]string{"/payments.[/Get"}},
+		"regexp": {MatchRegexp(regexp.MustCompile(`^/payments\.\w+/(Get|List)`)),
+			[]string{"/payments.Payments/Get", "/payments.Refunds/ListAll"},
+			[]string{"/payments.Payments/Charge"}},
+		"and": {And(MatchPackage("payments"), MatchGlob("/*/Get")),
+			[]string{"/payments.Payments/Get"},
+			[]string{"/payments.Payments/Charge", "/catalog.Catalog/Get"}},
+		"empty and": {And(), []string{"/a.A/B"}, nil},
+		"or": {Or(MatchService("payments.Payments"), MatchService("catalog.Catalog")),
+			[]string{"/payments.Payments/Get", "/catalog.Catalog/List"},
+			[]string{"/payments.Refunds/Get"}},
+		"empty or": {Or(), nil, []string{"/a.A/B"}},
+		"not": {Not(HealthMatcher),
+			[]string{"/payments.Payments/Get"},
+			[]string{"/grpc.health.v1.Health/Check"}},
+		"infra": {InfraMatcher,
+			[]string{"/grpc.health.v1.Health/Watch", "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", "/grpc.channelz.v1.Channelz/GetServers"},
+			[]string{"/grpc.health.v2.Health/Check"}},
+	} {
+		for _, m := range tc.matches {
+			if !tc.matcher(m) {
+				t.Errorf("%s: %s not matched", name, m)
+			}
+		}
+		for _, m := range tc.misses {
+			if tc.matcher(m) {
+				t.Errorf("%s: %s matched", name, m)
+			}
+		}
+	}
+}
+
+func TestMatchRegexpNil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("nil regexp accepted")
+		}
+	}()
+	MatchRegexp(nil)
+}
+
+func TestLevelOfFirstMatch(t *testing.T) {
+	rules := []LevelRule{
+		MatchMethod("/payments.Payments/Charge").Level(zerolog.DebugLevel),
+		MatchPackage("payments").Level(zerolog.WarnLevel),
+		MatchGlob("/payments.Payments/*").Level(zerolog.ErrorLevel),
+	}
+	for method, want := range map[string]zerolog.Level{
+		"/payments.Payments/Charge": zerolog.DebugLevel,
+		"/payments.Payments/Get":    zerolog.WarnLevel, // the package rule goes before the glob
+		"/catalog.Catalog/Get":      zerolog.NoLevel,
+	} {
+		l, ok := levelOf(rules, method)
+		if l != want || ok != (want != zerolog.NoLevel) {
+			t.Errorf("%s: got %v %v, want %v", method, l, ok, want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package grpc_zerolog
 
 import (
+	"context"
+	"time"
+
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
 )
@@ -21,9 +24,16 @@ var (
 		return true
 	}
 
+	// DefaultClock is the Clock backed by time package
+	DefaultClock Clock = systemClock{}
+
 	defaultOptions = &options{
-		levelFunc: DefaultCodeToLevelFunc,
-		shouldLog: DefaultDeciderFunc,
+		levelFunc:    DefaultCodeToLevelFunc,
+		shouldLog:    DefaultDeciderFunc,
+		metadataKeys: func() []string { return nil },
+		clock:        DefaultClock,
+		fields:       DefaultFieldNames,
+		messages:     [callKinds]message{msgUnary, msgServerStream, msgClientStream, msgStatsEnd, msgAttempt},
 	}
 )
 
@@ -33,6 +43,23 @@ type CodeToLevel func(code codes.Code) zerolog.Level
 // Decider function defines rules for suppressing any interceptor logs
 type Decider func(fullMethodName string, err error) bool
 
+// Clock provides the time for start times and durations logged by interceptors.
+// The deadlines of contexts, the deadline budget and the retry backoff are the wall time and not affected by the clock.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
 // Option used to configure the interceptors
 type Option func(*options)
 
@@ -50,9 +77,105 @@ func WithDecider(f Decider) Option {
 	}
 }
 
+// WithLevelRegistry sets the registry of per method log levels.
+// The overridden level applies to the logger populated into the context and to the logger of finished call statement.
+// It cannot go below zerolog.GlobalLevel, which filters the statements of all loggers.
+func WithLevelRegistry(r *LevelRegistry) Option {
+	return func(o *options) {
+		o.levels = r
+	}
+}
+
+// WithLevelRules sets the log level of methods by the first matching rule, the LevelRegistry overrides win over the rules.
+// The level applies to the logger populated into the context and to the logger of finished call statement.
+func WithLevelRules(rules ...LevelRule) Option {
+	return func(o *options) {
+		o.levelRules = rules
+	}
+}
+
+// WithInfraSuppression logs the calls of infrastructure methods matched by InfraMatcher only if they fail.
+// The successful calls are counted and logged per method once in the interval of wall time, zero interval disables the summary.
+func WithInfraSuppression(interval time.Duration) Option {
+	return func(o *options) {
+		o.infra = &infraSuppressor{matcher: InfraMatcher, interval: interval}
+	}
+}
+
+// WithClock replaces the clock of interceptors, e.g. by a fake clock making the logs deterministic in tests
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithFieldNames sets the names of call fields, see DefaultFieldNames, ECSFieldNames and OTelFieldNames
+func WithFieldNames(names FieldNames) Option {
+	return func(o *options) {
+		o.fields = names
+	}
+}
+
+// WithDurationFormat sets the format of call duration and deadline budget fields, use it after WithFieldNames which sets the format as well
+func WithDurationFormat(f DurationFormat) Option {
+	return func(o *options) {
+		o.fields.DurationFormat = f
+	}
+}
+
+// WithMetadataKeys logs the values of metadata keys as grpc.metadata.<key> fields.
+// The server interceptors log the incoming metadata, the client ones log the outgoing metadata.
+func WithMetadataKeys(keys ...string) Option {
+	return func(o *options) {
+		o.metadataKeys = func() []string { return keys }
+	}
+}
+
+// WithMessage sets the message of call statements of kind
+func WithMessage(kind CallKind, msg string) Option {
+	return func(o *options) {
+		o.messages[kind] = message(msg)
+	}
+}
+
+// WithHook adds the hook called with the event of call statement before it is written, hooks are called in order of adding
+func WithHook(h CallHook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks[:len(o.hooks):len(o.hooks)], h)
+	}
+}
+
+// WithShutdownContext sets the context done when the server shuts down,
+// server calls cancelled after it are logged with the CancelShutdown reason
+func WithShutdownContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.shutdown = ctx
+	}
+}
+
+// WithCancelTracking watches the context of every server call by a goroutine to log the exact time it ended
+// before the handler returned. Without it the calls which context ended are logged with the time the handler returned,
+// so the cancel overrun is zero.
+func WithCancelTracking() Option {
+	return func(o *options) {
+		o.cancelTracking = true
+	}
+}
+
 type options struct {
-	levelFunc CodeToLevel
-	shouldLog Decider
+	levelFunc    CodeToLevel
+	shouldLog    Decider
+	levels       *LevelRegistry
+	levelRules   []LevelRule
+	metadataKeys func() []string
+	infra        *infraSuppressor
+	clock        Clock
+	fields       FieldNames
+	messages     [callKinds]message
+	hooks        []CallHook
+	shutdown     context.Context
+	// cancelTracking enables the goroutine watching the context of server calls
+	cancelTracking bool
 }
 
 func evaluateOptions(opts []Option) *options {
@@ -61,5 +184,17 @@ func evaluateOptions(opts []Option) *options {
 	for _, o := range opts {
 		o(optCopy)
 	}
+	if optCopy.infra != nil {
+		// every interceptor counts and logs its own calls
+		optCopy.infra = &infraSuppressor{matcher: optCopy.infra.matcher, interval: optCopy.infra.interval, counts: make(map[string]int)}
+	}
 	return optCopy
 }
+
+// loggerFor returns the logger with level set by the level rules and registry for method
+func (o *options) loggerFor(logger zerolog.Logger, method string) zerolog.Logger {
+	if l, ok := levelOf(o.levelRules, method); ok {
+		logger = logger.Level(l)
+	}
+	return o.levels.apply(logger, method)
+}
@@ -24,17 +24,17 @@ func NewPayloadUnaryServerInterceptor(logger zerolog.Logger, opts ...PayloadOpti
 			ret, err := handler(ctx, req)
 			yes, level := o.shouldLogErrors(info.FullMethod, err)
 			if yes {
-				l := initLog(nil, logger, info.FullMethod).Logger()
-				logProtoMessageAsJson(l.With().Str("reason", "unary call returns error").Logger(), level, req, msgPayloadRequest)
+				l := initLog(nil, logger, info.FullMethod, &o.fields).Logger()
+				logProtoMessageAsJson(l.With().Str("reason", "unary call returns error").Logger(), level, req, msgPayloadRequest, o.redact())
 			}
 			return ret, err
 		}
 
-		l := initLog(nil, logger, info.FullMethod).Logger()
-		logProtoMessageAsJson(l, o.level, req, msgPayloadRequest)
+		l := initLog(nil, o.loggerFor(logger, info.FullMethod), info.FullMethod, &o.fields).Logger()
+		logProtoMessageAsJson(l, o.level, req, msgPayloadRequest, o.redact())
 		res, err := handler(ctx, req)
 		if err == nil {
-			logProtoMessageAsJson(l, o.level, res, msgPayloadResponse)
+			logProtoMessageAsJson(l, o.level, res, msgPayloadResponse, o.redact())
 		}
 		return res, err
 	}
@@ -48,17 +48,17 @@ func NewPayloadUnaryClientInterceptor(logger zerolog.Logger, opts ...PayloadOpti
 			err := invoker(ctx, method, req, reply, cc, opts...)
 			yes, level := o.shouldLogErrors(method, err)
 			if yes {
-				l := initLog(nil, logger, method).Logger()
-				logProtoMessageAsJson(l.With().Str("reason", "unary call returns error").Logger(), level, req, msgPayloadRequest)
+				l := initLog(nil, logger, method, &o.fields).Logger()
+				logProtoMessageAsJson(l.With().Str("reason", "unary call returns error").Logger(), level, req, msgPayloadRequest, o.redact())
 			}
 			return err
 		}
 
-		l := initLog(nil, logger, method).Logger()
-		logProtoMessageAsJson(l, o.level, req, msgPayloadRequest)
+		l := initLog(nil, o.loggerFor(logger, method), method, &o.fields).Logger()
+		logProtoMessageAsJson(l, o.level, req, msgPayloadRequest, o.redact())
 		err := invoker(ctx, method, req, reply, cc, opts...)
 		if err == nil {
-			logProtoMessageAsJson(l, o.level, reply, msgPayloadResponse)
+			logProtoMessageAsJson(l, o.level, reply, msgPayloadResponse, o.redact())
 		}
 		return err
 	}
@@ -72,8 +72,8 @@ func NewPayloadStreamServerInterceptor(logger zerolog.Logger, opts ...PayloadOpt
 			return handler(srv, ss)
 		}
 
-		l := initLog(nil, logger, info.FullMethod).Logger()
-		newStream := &loggingServerStream{ServerStream: ss, l: l, level: o.level}
+		l := initLog(nil, o.loggerFor(logger, info.FullMethod), info.FullMethod, &o.fields).Logger()
+		newStream := &loggingServerStream{ServerStream: ss, l: l, level: o.level, redact: o.redact()}
 		return handler(srv, newStream)
 	}
 }
@@ -86,19 +86,22 @@ func NewPayloadStreamClientInterceptor(logger zerolog.Logger, opts ...PayloadOpt
 			return streamer(ctx, desc, cc, method, opts...)
 		}
 
-		l := initLog(nil, logger, method).Logger()
+		l := initLog(nil, o.loggerFor(logger, method), method, &o.fields).Logger()
 		cs, err := streamer(ctx, desc, cc, method, opts...)
-		newStream := &loggingClientStream{ClientStream: cs, l: l, level: o.level}
+		newStream := &loggingClientStream{ClientStream: cs, l: l, level: o.level, redact: o.redact()}
 		return newStream, err
 	}
 }
 
 type payloadMessage string
 
-func logProtoMessageAsJson(logger zerolog.Logger, level zerolog.Level, pbMsg interface{}, key payloadMessage) {
+func logProtoMessageAsJson(logger zerolog.Logger, level zerolog.Level, pbMsg interface{}, key payloadMessage, redact []string) {
 	if p, ok := pbMsg.(proto.Message); ok {
 		m := &jsonpbMarshalleble{p}
 		json, err := m.MarshalJSON()
+		if err == nil {
+			json, err = redactJSON(json, redact)
+		}
 		if err != nil {
 			logger.WithLevel(level).Err(err).Msg("Failed to marshal message")
 		}
@@ -108,14 +111,15 @@ func logProtoMessageAsJson(logger zerolog.Logger, level zerolog.Level, pbMsg int
 
 type loggingServerStream struct {
 	grpc.ServerStream
-	l     zerolog.Logger
-	level zerolog.Level
+	l      zerolog.Logger
+	level  zerolog.Level
+	redact []string
 }
 
 func (s *loggingServerStream) SendMsg(m interface{}) error {
 	err := s.ServerStream.SendMsg(m)
 	if err == nil {
-		logProtoMessageAsJson(s.l, s.level, m, msgPayloadResponse)
+		logProtoMessageAsJson(s.l, s.level, m, msgPayloadResponse, s.redact)
 	}
 	return err
 }
@@ -123,21 +127,22 @@ func (s *loggingServerStream) SendMsg(m interface{}) error {
 func (s *loggingServerStream) RecvMsg(m interface{}) error {
 	err := s.ServerStream.RecvMsg(m)
 	if err == nil {
-		logProtoMessageAsJson(s.l, s.level, m, msgPayloadRequest)
+		logProtoMessageAsJson(s.l, s.level, m, msgPayloadRequest, s.redact)
 	}
 	return err
 }
 
 type loggingClientStream struct {
 	grpc.ClientStream
-	l     zerolog.Logger
-	level zerolog.Level
+	l      zerolog.Logger
+	level  zerolog.Level
+	redact []string
 }
 
 func (s *loggingClientStream) SendMsg(m interface{}) error {
 	err := s.ClientStream.SendMsg(m)
 	if err == nil {
-		logProtoMessageAsJson(s.l, s.level, m, msgPayloadRequest)
+		logProtoMessageAsJson(s.l, s.level, m, msgPayloadRequest, s.redact)
 	}
 	return err
 }
@@ -145,7 +150,7 @@ func (s *loggingClientStream) SendMsg(m interface{}) error {
 func (s *loggingClientStream) RecvMsg(m interface{}) error {
 	err := s.ClientStream.RecvMsg(m)
 	if err == nil {
-		logProtoMessageAsJson(s.l, s.level, m, msgPayloadResponse)
+		logProtoMessageAsJson(s.l, s.level, m, msgPayloadResponse, s.redact)
 	}
 	return err
 }
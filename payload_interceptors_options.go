@@ -26,6 +26,8 @@ var (
 		decider:         DefaultPayloadDecider,
 		shouldLogErrors: DefaultLogErrorsDecider,
 		level:           DefaultPayloadLogLevel,
+		redact:          func() []string { return nil },
+		fields:          DefaultFieldNames,
 	}
 )
 
@@ -51,6 +53,38 @@ func WithPayloadLevel(l zerolog.Level) PayloadOption {
 	}
 }
 
+// WithPayloadRegistry sets the registry that enables the payload logging per method at runtime.
+// The payload of enabled methods is logged even if the logger level is above the payload level,
+// but not if zerolog.GlobalLevel is above it, the global level cannot be overridden.
+func WithPayloadRegistry(r *LevelRegistry) PayloadOption {
+	return func(o *payloadOptions) {
+		o.levels = r
+	}
+}
+
+// WithPayloadRedaction replaces the values of payload fields at the paths with "[REDACTED]".
+// The path is dot separated JSON names of fields as they are logged, like "card.number".
+func WithPayloadRedaction(paths ...string) PayloadOption {
+	return func(o *payloadOptions) {
+		o.redact = func() []string { return paths }
+	}
+}
+
+// WithPayloadInfraSuppression disables the payload logging of infrastructure methods matched by InfraMatcher,
+// the request payload is still logged on failure according to LogErrorsDecider
+func WithPayloadInfraSuppression() PayloadOption {
+	return func(o *payloadOptions) {
+		o.skip = InfraMatcher
+	}
+}
+
+// WithPayloadFieldNames sets the names of call fields, use the same names as in WithFieldNames of logging interceptors
+func WithPayloadFieldNames(names FieldNames) PayloadOption {
+	return func(o *payloadOptions) {
+		o.fields = names
+	}
+}
+
 // PayloadOption used to configure the payload interceptors
 type PayloadOption func(*payloadOptions)
 
@@ -64,6 +98,10 @@ type payloadOptions struct {
 	decider         PayloadDecider
 	shouldLogErrors LogErrorsDecider
 	level           zerolog.Level
+	levels          *LevelRegistry
+	redact          func() []string
+	skip            Matcher
+	fields          FieldNames
 }
 
 func evaluatePayloadOptions(opts []PayloadOption) *payloadOptions {
@@ -77,8 +115,11 @@ func evaluatePayloadOptions(opts []PayloadOption) *payloadOptions {
 
 func (o *payloadOptions) shouldLog(method string) bool {
 	gl := zerolog.GlobalLevel()
+	enabled := o.levels.PayloadEnabled(method)
 	switch {
-	case !o.decider(method):
+	case !enabled && !o.decider(method):
+		return false
+	case !enabled && o.skip != nil && o.skip(method):
 		return false
 	case gl == zerolog.NoLevel, o.level == zerolog.NoLevel:
 		return false
@@ -88,3 +129,11 @@ func (o *payloadOptions) shouldLog(method string) bool {
 		return true
 	}
 }
+
+// loggerFor returns the logger for payloads of method, lowering its level if the payload logging is enabled by registry
+func (o *payloadOptions) loggerFor(logger zerolog.Logger, method string) zerolog.Logger {
+	if o.levels.PayloadEnabled(method) && logger.GetLevel() > o.level {
+		return logger.Level(o.level)
+	}
+	return logger
+}
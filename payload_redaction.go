@@ -0,0 +1,45 @@
+package grpc_zerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// redactJSON replaces the values of fields at the dot separated paths, like "card.number", with the placeholder.
+// The paths use the JSON names of fields as they are logged, the arrays on the path are redacted element-wise.
+func redactJSON(data []byte, paths []string) ([]byte, error) {
+	if len(paths) == 0 {
+		return data, nil
+	}
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		redactPath(v, strings.Split(p, "."))
+	}
+	return json.Marshal(v)
+}
+
+func redactPath(v interface{}, path []string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		f, ok := t[path[0]]
+		switch {
+		case !ok:
+		case len(path) == 1:
+			t[path[0]] = redactedValue
+		default:
+			redactPath(f, path[1:])
+		}
+	case []interface{}:
+		for _, e := range t {
+			redactPath(e, path)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package grpc_zerolog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is the declarative logging configuration of interceptors, see LoadPolicy.
+// The method patterns are full method names or path.Match globs like "/payments.Payments/*",
+// the longest matching pattern wins.
+//
+// An example of YAML policy, the JSON with the same keys is accepted as well:
+//
+//	levels:
+//	  "/payments.Payments/*": debug
+//	suppress:
+//	  - "/grpc.health.v1.Health/*"
+//	errors_only:
+//	  - "/catalog.Catalog/List*"
+//	sampling:
+//	  "/catalog.Catalog/Get": 0.1
+//	metadata:
+//	  - x-request-id
+//	payload:
+//	  methods:
+//	    - "/payments.Payments/Charge"
+//	  redact:
+//	    - card.number
+type Policy struct {
+	// Levels overrides the log level of methods, see WithLevelRegistry
+	Levels map[string]string `yaml:"levels"`
+	// Suppress lists the methods that are never logged
+	Suppress []string `yaml:"suppress"`
+	// ErrorsOnly lists the methods logged only if the call returns error
+	ErrorsOnly []string `yaml:"errors_only"`
+	// Sampling sets the rate of logged successful calls of methods, from 0 to 1
+	Sampling map[string]float64 `yaml:"sampling"`
+	// Metadata lists the metadata keys logged as fields, see WithMetadataKeys
+	Metadata []string `yaml:"metadata"`
+	// Payload configures the payload interceptors
+	Payload PayloadPolicy `yaml:"payload"`
+
+	once     sync.Once
+	registry *LevelRegistry
+}
+
+// PayloadPolicy is the declarative configuration of payload interceptors
+type PayloadPolicy struct {
+	// Methods lists the methods with payload logging enabled, the payload of other methods is not logged
+	Methods []string `yaml:"methods"`
+	// Redact lists the paths of payload fields to redact, see WithPayloadRedaction
+	Redact []string `yaml:"redact"`
+}
+
+// PolicyError describes all problems found in the policy
+type PolicyError struct {
+	Problems []string
+}
+
+func (e *PolicyError) Error() string {
+	return "invalid logging policy: " + strings.Join(e.Problems, "; ")
+}
+
+// LoadPolicy reads and validates the YAML or JSON policy, the unknown keys are errors
+func LoadPolicy(r io.Reader) (*Policy, error) {
+	p := &Policy{}
+	d := yaml.NewDecoder(r)
+	d.KnownFields(true)
+	if err := d.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, &PolicyError{Problems: []string{err.Error()}}
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate returns *PolicyError if the policy has invalid patterns, levels or sampling rates
+func (p *Policy) Validate() error {
+	var problems []string
+	checkPatterns := func(key string, patterns []string) {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				problems = append(problems, fmt.Sprintf("%s: invalid method pattern %q", key, pattern))
+			}
+		}
+	}
+
+	levelPatterns := make([]string, 0, len(p.Levels))
+	for pattern := range p.Levels {
+		levelPatterns = append(levelPatterns, pattern)
+	}
+	sort.Strings(levelPatterns)
+	for _, pattern := range levelPatterns {
+		checkPatterns("levels", []string{pattern})
+		if _, err := zerolog.ParseLevel(p.Levels[pattern]); err != nil || p.Levels[pattern] == "" {
+			problems = append(problems, fmt.Sprintf("levels[%q]: unknown level %q", pattern, p.Levels[pattern]))
+		}
+	}
+	checkPatterns("suppress", p.Suppress)
+	checkPatterns("errors_only", p.ErrorsOnly)
+	samplingPatterns := make([]string, 0, len(p.Sampling))
+	for pattern := range p.Sampling {
+		samplingPatterns = append(samplingPatterns, pattern)
+	}
+	sort.Strings(samplingPatterns)
+	for _, pattern := range samplingPatterns {
+		checkPatterns("sampling", []string{pattern})
+		if r := p.Sampling[pattern]; r < 0 || r > 1 {
+			problems = append(problems, fmt.Sprintf("sampling[%q]: rate %v is out of range [0, 1]", pattern, r))
+		}
+	}
+	for _, k := range p.Metadata {
+		if k == "" {
+			problems = append(problems, "metadata: empty key")
+		}
+	}
+	checkPatterns("payload.methods", p.Payload.Methods)
+	for _, r := range p.Payload.Redact {
+		if r == "" || strings.Contains(r, "..") || strings.HasPrefix(r, ".") || strings.HasSuffix(r, ".") {
+			problems = append(problems, fmt.Sprintf("payload.redact: invalid path %q", r))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &PolicyError{Problems: problems}
+	}
+	return nil
+}
+
+// Options returns the interceptor options implementing the policy.
+// The policy levels are set to the registry, so the registry driven by the admin service can override them.
+// The nil registry means the registry of the policy, which is shared with PayloadOptions.
+func (p *Policy) Options(registry *LevelRegistry) []Option {
+	return newPolicyHolder(p, p.registryOr(registry)).options()
+}
+
+// PayloadOptions returns the payload interceptor options implementing the policy, see Options for the registry
+func (p *Policy) PayloadOptions(registry *LevelRegistry) []PayloadOption {
+	return newPolicyHolder(p, p.registryOr(registry)).payloadOptions()
+}
+
+func (p *Policy) registryOr(registry *LevelRegistry) *LevelRegistry {
+	if registry != nil {
+		return registry
+	}
+	p.once.Do(func() { p.registry = NewLevelRegistry() })
+	return p.registry
+}
+
+func (p *Policy) decide(fullMethodName string, err error) bool {
+	switch {
+	case matchAny(p.Suppress, fullMethodName):
+		return false
+	case err != nil:
+		return true
+	case matchAny(p.ErrorsOnly, fullMethodName):
+		return false
+	}
+	found, rate := "", 1.0
+	for pattern, r := range p.Sampling {
+		// the lexically first of equally long patterns wins, as in LevelRegistry
+		if found != "" && (len(pattern) < len(found) || len(pattern) == len(found) && pattern > found) {
+			continue
+		}
+		if ok, _ := path.Match(pattern, fullMethodName); ok {
+			found, rate = pattern, r
+		}
+	}
+	return found == "" || rand.Float64() < rate
+}
+
+// policyHolder keeps the current policy, the options built by it read the policy on every call
+type policyHolder struct {
+	current  atomic.Value
+	registry *LevelRegistry
+}
+
+func newPolicyHolder(p *Policy, registry *LevelRegistry) *policyHolder {
+	h := &policyHolder{registry: registry}
+	h.set(p)
+	return h
+}
+
+func (h *policyHolder) policy() *Policy {
+	return h.current.Load().(*Policy)
+}
+
+func (h *policyHolder) set(p *Policy) {
+	levels := make(map[string]zerolog.Level, len(p.Levels))
+	for pattern, l := range p.Levels {
+		levels[pattern], _ = zerolog.ParseLevel(l)
+	}
+	h.current.Store(p)
+	h.registry.setPolicy(levels, p.Payload.Methods)
+}
+
+func (h *policyHolder) options() []Option {
+	return []Option{
+		WithLevelRegistry(h.registry),
+		WithDecider(func(fullMethodName string, err error) bool {
+			return h.policy().decide(fullMethodName, err)
+		}),
+		func(o *options) {
+			o.metadataKeys = func() []string { return h.policy().Metadata }
+		},
+	}
+}
+
+func (h *policyHolder) payloadOptions() []PayloadOption {
+	return []PayloadOption{
+		WithPayloadRegistry(h.registry),
+		WithPayloadDecider(func(fullMethodName string) bool { return false }),
+		func(o *payloadOptions) {
+			o.redact = func() []string { return h.policy().Payload.Redact }
+		},
+	}
+}
+
+func matchAny(patterns []string, fullMethodName string) bool {
+	_, ok := longestMatch(patterns, fullMethodName)
+	return ok
+}
+
+func longestMatch(patterns []string, fullMethodName string) (string, bool) {
+	found := ""
+	for _, p := range patterns {
+		if len(p) <= len(found) {
+			continue
+		}
+		if ok, _ := path.Match(p, fullMethodName); ok {
+			found = p
+		}
+	}
+	return found, found != ""
+}
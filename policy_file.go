@@ -0,0 +1,120 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// PolicyFile keeps the Policy loaded from the file and reloads it when the file changes.
+// The options built by PolicyFile follow the reloaded policy without rebuilding the interceptors,
+// so the file can be mounted from a Kubernetes ConfigMap and changed at runtime.
+type PolicyFile struct {
+	path   string
+	holder *policyHolder
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+}
+
+// OpenPolicyFile loads the policy from the YAML or JSON file.
+// The policy levels are set to the registry, pass the registry of the admin service to override them at runtime,
+// the nil registry means a new one.
+func OpenPolicyFile(path string, registry *LevelRegistry) (*PolicyFile, error) {
+	f := &PolicyFile{path: path}
+	p, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	if registry == nil {
+		registry = NewLevelRegistry()
+	}
+	f.holder = newPolicyHolder(p, registry)
+	return f, nil
+}
+
+// Policy returns the current policy
+func (f *PolicyFile) Policy() *Policy {
+	return f.holder.policy()
+}
+
+// Options returns the interceptor options following the current policy
+func (f *PolicyFile) Options() []Option {
+	return f.holder.options()
+}
+
+// PayloadOptions returns the payload interceptor options following the current policy
+func (f *PolicyFile) PayloadOptions() []PayloadOption {
+	return f.holder.payloadOptions()
+}
+
+// Reload loads the policy from the file, the current policy is kept if the new one is invalid
+func (f *PolicyFile) Reload() error {
+	p, err := f.load()
+	if err != nil {
+		return err
+	}
+	f.holder.set(p)
+	return nil
+}
+
+// Watch checks the file every interval and reloads the policy if the file is changed, until ctx is done.
+// The errors of reloading are passed to onError, which may be nil. Run it in a separate goroutine.
+func (f *PolicyFile) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		changed, err := f.changed()
+		if err == nil && changed {
+			err = f.Reload()
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+func (f *PolicyFile) changed() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, fmt.Errorf("logging policy %s: %w", f.path, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+	// the invalid file is reported once, not on every check
+	f.modTime, f.size = info.ModTime(), info.Size()
+	return true, nil
+}
+
+func (f *PolicyFile) load() (*Policy, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("logging policy %s: %w", f.path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("logging policy %s: %w", f.path, err)
+	}
+	p, err := LoadPolicy(file)
+	if err != nil {
+		return nil, fmt.Errorf("logging policy %s: %w", f.path, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.modTime, f.size = info.ModTime(), info.Size()
+	return p, nil
+}
@@ -0,0 +1,138 @@
+package grpc_zerolog
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	for name, tc := range map[string]struct {
+		input    string
+		problems []string
+	}{
+		"empty": {input: ""},
+		"valid yaml": {input: `
+levels:
+  "/payments.Payments/*": debug
+sampling:
+  "/catalog.Catalog/Get": 0.1
+payload:
+  redact: [card.number]
+`},
+		"valid json":    {input: `{"levels": {"/payments.Payments/*": "debug"}, "suppress": ["/grpc.health.v1.Health/*"]}`},
+		"unknown key":   {input: "level:\n  /a.A/B: debug\n", problems: []string{"field level not found"}},
+		"nested key":    {input: "payload:\n  method: [/a.A/B]\n", problems: []string{"field method not found"}},
+		"bad glob":      {input: "suppress: [\"/a.A/[\"]\n", problems: []string{`suppress: invalid method pattern "/a.A/["`}},
+		"empty pattern": {input: "errors_only: [\"\"]\n", problems: []string{`errors_only: invalid method pattern ""`}},
+		"bad level":     {input: "levels:\n  /a.A/B: verbose\n", problems: []string{`levels["/a.A/B"]: unknown level "verbose"`}},
+		"sampling": {input: "sampling:\n  /a.A/B: 1.5\n  /a.A/C: -0.1\n", problems: []string{
+			`sampling["/a.A/B"]: rate 1.5 is out of range [0, 1]`,
+			`sampling["/a.A/C"]: rate -0.1 is out of range [0, 1]`,
+		}},
+		"redact": {input: "payload:\n  redact: [card..number]\n", problems: []string{`payload.redact: invalid path "card..number"`}},
+	} {
+		_, err := LoadPolicy(strings.NewReader(tc.input))
+		if len(tc.problems) == 0 {
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+			continue
+		}
+		var pe *PolicyError
+		if !errors.As(err, &pe) {
+			t.Errorf("%s: got %v, want PolicyError", name, err)
+			continue
+		}
+		if len(pe.Problems) != len(tc.problems) {
+			t.Errorf("%s: got problems %q, want %q", name, pe.Problems, tc.problems)
+			continue
+		}
+		for i, want := range tc.problems {
+			if !strings.Contains(pe.Problems[i], want) {
+				t.Errorf("%s: got problem %q, want %q", name, pe.Problems[i], want)
+			}
+		}
+	}
+}
+
+func TestPolicyDecide(t *testing.T) {
+	p := &Policy{
+		Suppress:   []string{"/a.A/Suppressed", "/b.B/*"},
+		ErrorsOnly: []string{"/a.A/*", "/b.B/*"},
+		Sampling: map[string]float64{
+			"/c.C/*":     0,
+			"/c.C/Every": 1,
+			"/a.A/*":     1,
+		},
+	}
+	failed := errors.New("failed")
+	for _, tc := range []struct {
+		method string
+		err    error
+		want   bool
+	}{
+		{"/a.A/Suppressed", failed, false}, // suppress wins over errors
+		{"/b.B/Get", failed, false},        // suppress wins over errors_only
+		{"/a.A/Get", nil, false},           // errors_only wins over sampling
+		{"/a.A/Get", failed, true},
+		{"/c.C/Get", nil, false}, // sampled out
+		{"/c.C/Get", failed, true},
+		{"/c.C/Every", nil, true}, // longest sampling pattern wins
+		{"/d.D/Get", nil, true},
+	} {
+		if got := p.decide(tc.method, tc.err); got != tc.want {
+			t.Errorf("decide(%s, %v) = %v, want %v", tc.method, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestPolicyFileReloadKeepsValidPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	write := func(content string) {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("levels:\n  /a.A/*: debug\n")
+	registry := NewLevelRegistry()
+	f, err := OpenPolicyFile(path, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write("levels:\n  /a.A/*: verbose\n")
+	var pe *PolicyError
+	if err := f.Reload(); !errors.As(err, &pe) {
+		t.Fatalf("got %v, want PolicyError", err)
+	}
+	if l, ok := registry.Level("/a.A/Get"); !ok || l != zerolog.DebugLevel {
+		t.Errorf("got level %v %v, want the old debug", l, ok)
+	}
+	if got := f.Policy().Levels["/a.A/*"]; got != "debug" {
+		t.Errorf("got policy level %q, want the old debug", got)
+	}
+
+	write("levels:\n  /a.A/*: warn\n")
+	if err := f.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if l, _ := registry.Level("/a.A/Get"); l != zerolog.WarnLevel {
+		t.Errorf("got level %v, want the reloaded warn", l)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Reload(); err == nil {
+		t.Errorf("missing file reloaded")
+	}
+	if l, _ := registry.Level("/a.A/Get"); l != zerolog.WarnLevel {
+		t.Errorf("got level %v, want warn kept", l)
+	}
+}
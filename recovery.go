@@ -0,0 +1,60 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/pereslava/grpc_zerolog/ctxzerolog"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const msgPanic message = "recovered from panic"
+
+// NewRecoveryUnaryServerInterceptor returns an unary server interceptor that recovers the panics of handlers.
+// The panic is logged with the stack trace and the call returns codes.Internal error.
+// It uses the logger from context if the logging interceptor goes before it in the chain.
+func NewRecoveryUnaryServerInterceptor(logger zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (_ interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(ctx, logger, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// NewRecoveryStreamServerInterceptor returns a streaming server interceptor that recovers the panics of handlers.
+// The panic is logged with the stack trace and the call returns codes.Internal error.
+// It uses the logger from context if the logging interceptor goes before it in the chain.
+func NewRecoveryStreamServerInterceptor(logger zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(stream.Context(), logger, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, stream)
+	}
+}
+
+func recoverPanic(ctx context.Context, logger zerolog.Logger, fullMethodName string, r interface{}) error {
+	with, ok := ctxLogger(ctx)
+	if !ok {
+		with = initLog(ctx, logger, fullMethodName, &DefaultFieldNames)
+	}
+	l := with.Str("grpc.panic", fmt.Sprint(r)).Str("stack", string(debug.Stack())).Logger()
+	l.Error().Msg(string(msgPanic))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// ctxLogger returns the logger populated into the context by server interceptors
+func ctxLogger(ctx context.Context) (zerolog.Context, bool) {
+	with := ctxzerolog.Get(ctx)
+	l := with.Logger()
+	return with, l.GetLevel() != zerolog.Disabled
+}
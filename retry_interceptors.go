@@ -0,0 +1,73 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	msgRetryBackoff message = "retrying call"
+	msgRetryStopped message = "stopped retrying call"
+
+	// RetryStopMaxAttempts is the reason of stopped retries when the attempts limit is reached
+	RetryStopMaxAttempts = "max_attempts"
+	// RetryStopDeadline is the reason of stopped retries when the backoff would exceed the deadline of the call
+	RetryStopDeadline = "deadline"
+	// RetryStopCanceled is the reason of stopped retries when the call is cancelled during the backoff
+	RetryStopCanceled = "canceled"
+)
+
+// NewRetryUnaryClientInterceptor returns an unary client interceptor that retries the calls failed with retryable codes.
+// Every attempt is logged as the finished attempt with grpc.attempt field, the backoff before the next attempt is logged
+// with Debug level and grpc.retry.backoff field, and the reason of stopped retries is logged as grpc.retry.stopped field.
+// The fields follow the options of WithRetryLogOptions, chain it after NewUnaryClientInterceptor to log the call summary.
+func NewRetryUnaryClientInterceptor(logger zerolog.Logger, opts ...RetryOption) grpc.UnaryClientInterceptor {
+	r := evaluateRetryOptions(opts)
+	o := evaluateOptions(r.options)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		l, limits := o.fields.callOptions(logMetadata(initLog(ctx, o.loggerFor(logger, method), method, &o.fields), outgoingMetadata(ctx), o.metadataKeys()), opts)
+		for attempt := 1; ; attempt++ {
+			start, wallStart := o.clock.Now(), time.Now()
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			with := l.Int("grpc.attempt", attempt)
+			if o.shouldLog(method, err) {
+				o.logCall(o.fields.messageSize(with, err, limits), CallInfo{Context: ctx, FullMethod: method, Kind: CallAttempt, Client: true, Err: err, Start: start, Duration: o.clock.Since(start), wallStart: wallStart})
+			}
+			if err == nil || !r.retryable(status.Code(err)) {
+				return err
+			}
+
+			if attempt >= r.maxAttempts {
+				logRetryStopped(with, RetryStopMaxAttempts)
+				return err
+			}
+			backoff := r.backoff.delay(attempt, rand.Float64())
+			// the deadline and the backoff timer are the wall time, the clock measures the logged durations only
+			if d, ok := ctx.Deadline(); ok && time.Until(d) < backoff {
+				logRetryStopped(with, RetryStopDeadline)
+				return err
+			}
+			rl := o.fields.durationField(with, "grpc.retry.backoff", backoff).Logger()
+			rl.Debug().Msg(string(msgRetryBackoff))
+
+			t := time.NewTimer(backoff)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				logRetryStopped(with, RetryStopCanceled)
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+	}
+}
+
+func logRetryStopped(with zerolog.Context, reason string) {
+	l := with.Str("grpc.retry.stopped", reason).Logger()
+	l.Debug().Msg(string(msgRetryStopped))
+}
@@ -0,0 +1,122 @@
+package grpc_zerolog
+
+import (
+	"math"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	// DefaultRetryCodes are the codes retried by default
+	DefaultRetryCodes = []codes.Code{codes.Unavailable}
+
+	// DefaultRetryMaxAttempts is the default limit of attempts including the first one
+	DefaultRetryMaxAttempts = 3
+
+	// DefaultRetryBackoff is the default backoff of retry interceptor
+	DefaultRetryBackoff = Backoff{
+		Initial:    100 * time.Millisecond,
+		Max:        5 * time.Second,
+		Multiplier: 2,
+		Jitter:     0.2,
+	}
+)
+
+// Backoff defines the exponential backoff between attempts, it's waited by the wall time whatever the Clock is
+type Backoff struct {
+	// Initial is the backoff before the second attempt
+	Initial time.Duration
+	// Max limits the backoff with the jitter applied, zero means the maximum time.Duration
+	Max time.Duration
+	// Multiplier multiplies the backoff for every next attempt
+	Multiplier float64
+	// Jitter randomizes the backoff by the fraction of it, 0.2 means ±20%
+	Jitter float64
+}
+
+// RetryOption used to configure the retry interceptor
+type RetryOption func(*retryOptions)
+
+// WithRetryCodes sets the codes to retry
+func WithRetryCodes(c ...codes.Code) RetryOption {
+	return func(o *retryOptions) {
+		o.codes = c
+	}
+}
+
+// WithRetryMaxAttempts limits the attempts including the first one
+func WithRetryMaxAttempts(n int) RetryOption {
+	return func(o *retryOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// WithRetryBackoff sets the backoff between attempts
+func WithRetryBackoff(b Backoff) RetryOption {
+	return func(o *retryOptions) {
+		o.backoff = b
+	}
+}
+
+// WithRetryLogOptions sets the options of logging the attempts, the same as of NewUnaryClientInterceptor
+func WithRetryLogOptions(opts ...Option) RetryOption {
+	return func(o *retryOptions) {
+		o.options = opts
+	}
+}
+
+type retryOptions struct {
+	codes       []codes.Code
+	maxAttempts int
+	backoff     Backoff
+	options     []Option
+}
+
+func evaluateRetryOptions(opts []RetryOption) *retryOptions {
+	o := &retryOptions{
+		codes:       DefaultRetryCodes,
+		maxAttempts: DefaultRetryMaxAttempts,
+		backoff:     DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *retryOptions) retryable(code codes.Code) bool {
+	for _, c := range o.codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the backoff before the attempt following the attempt number n
+func (b Backoff) delay(n int, random float64) time.Duration {
+	max := float64(b.Max)
+	if b.Max <= 0 {
+		max = math.MaxInt64
+	}
+	d := float64(b.Initial)
+	for i := 1; i < n && d < max; i++ {
+		d *= b.Multiplier
+	}
+	if d > max {
+		d = max
+	}
+	d += d * b.Jitter * (2*random - 1)
+	if d > max {
+		d = max
+	}
+	switch {
+	case d < 0:
+		return 0
+	case d >= math.MaxInt64:
+		// the float is rounded up to 2^63, which overflows time.Duration
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
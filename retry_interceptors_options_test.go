@@ -0,0 +1,28 @@
+package grpc_zerolog
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	for name, tc := range map[string]struct {
+		backoff Backoff
+		n       int
+		random  float64
+		want    time.Duration
+	}{
+		"initial":              {Backoff{Initial: time.Second, Multiplier: 2}, 1, 0.5, time.Second},
+		"multiplied":           {Backoff{Initial: time.Second, Multiplier: 2}, 3, 0.5, 4 * time.Second},
+		"max":                  {Backoff{Initial: time.Second, Max: 3 * time.Second, Multiplier: 2}, 3, 0.5, 3 * time.Second},
+		"jitter":               {Backoff{Initial: time.Second, Multiplier: 2, Jitter: 0.5}, 1, 0, 500 * time.Millisecond},
+		"max with jitter":      {Backoff{Initial: time.Second, Max: 3 * time.Second, Multiplier: 2, Jitter: 0.5}, 3, 1, 3 * time.Second},
+		"overflow":             {Backoff{Initial: time.Second, Multiplier: 2}, 2000, 0.5, math.MaxInt64},
+		"overflow with jitter": {Backoff{Initial: time.Second, Multiplier: 2, Jitter: 0.2}, 63, 1, math.MaxInt64},
+	} {
+		if got := tc.backoff.delay(tc.n, tc.random); got != tc.want {
+			t.Errorf("%s: got %v, want %v", name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package grpc_zerolog_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var retryTestBackoff = grpc_zerolog.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}
+
+// dialRetry returns the client of the health service failing with Unavailable the failures first checks,
+// the client retries the checks by the retry interceptor logging into the recorder
+func dialRetry(t *testing.T, failures int32, opts ...grpc_zerolog.RetryOption) (healthpb.HealthClient, *unavailableHealth, *grpczerologtest.Recorder) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	h := &unavailableHealth{failures: failures}
+	healthpb.RegisterHealthServer(s, h)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	logs := grpczerologtest.NewRecorder()
+	cc, err := grpc.Dial("bufconn",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(grpc_zerolog.NewRetryUnaryClientInterceptor(logs.Logger(), opts...)),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return healthpb.NewHealthClient(cc), h, logs
+}
+
+// assertRetries checks the number of finished attempts and the reason of stopped retries, empty if retries did not stop
+func assertRetries(t *testing.T, logs *grpczerologtest.Recorder, attempts int, stopped string) {
+	t.Helper()
+	var n int
+	var reason interface{}
+	for _, e := range logs.CallEntries(checkMethod) {
+		switch e.Message {
+		case "finished attempt":
+			n++
+		case "stopped retrying call":
+			reason = e.Fields["grpc.retry.stopped"]
+		}
+	}
+	if n != attempts {
+		t.Errorf("got %d attempts, want %d, captured:\n%s", n, attempts, logs)
+	}
+	if stopped == "" && reason != nil || stopped != "" && reason != stopped {
+		t.Errorf("got stopped reason %v, want %q, captured:\n%s", reason, stopped, logs)
+	}
+}
+
+func calls(h *unavailableHealth, failures int32) int32 {
+	return failures - atomic.LoadInt32(&h.failures)
+}
+
+func TestRetrySucceeded(t *testing.T) {
+	client, _, logs := dialRetry(t, 2, grpc_zerolog.WithRetryBackoff(retryTestBackoff))
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	assertRetries(t, logs, 3, "")
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	client, h, logs := dialRetry(t, 5, grpc_zerolog.WithRetryCodes(codes.Aborted), grpc_zerolog.WithRetryBackoff(retryTestBackoff))
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want Unavailable", err)
+	}
+	if n := calls(h, 5); n != 1 {
+		t.Errorf("got %d calls, want 1", n)
+	}
+	assertRetries(t, logs, 1, "")
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	client, h, logs := dialRetry(t, 5, grpc_zerolog.WithRetryMaxAttempts(3), grpc_zerolog.WithRetryBackoff(retryTestBackoff))
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want Unavailable", err)
+	}
+	if n := calls(h, 5); n != 3 {
+		t.Errorf("got %d calls, want 3", n)
+	}
+	assertRetries(t, logs, 3, grpc_zerolog.RetryStopMaxAttempts)
+}
+
+func TestRetryDeadline(t *testing.T) {
+	client, h, logs := dialRetry(t, 5, grpc_zerolog.WithRetryBackoff(grpc_zerolog.Backoff{Initial: time.Minute, Multiplier: 1}))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want Unavailable", err)
+	}
+	if d := time.Since(start); d >= time.Second {
+		t.Errorf("returned after %v, want at once", d)
+	}
+	if n := calls(h, 5); n != 1 {
+		t.Errorf("got %d calls, want 1", n)
+	}
+	assertRetries(t, logs, 1, grpc_zerolog.RetryStopDeadline)
+}
+
+func TestRetryCanceledDuringBackoff(t *testing.T) {
+	client, h, logs := dialRetry(t, 5, grpc_zerolog.WithRetryBackoff(grpc_zerolog.Backoff{Initial: time.Minute, Multiplier: 1}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("got %v, want Canceled", err)
+	}
+	if n := calls(h, 5); n != 1 {
+		t.Errorf("got %d calls, want 1", n)
+	}
+	assertRetries(t, logs, 1, grpc_zerolog.RetryStopCanceled)
+}
@@ -0,0 +1,135 @@
+package grpc_zerolog
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+)
+
+const (
+	msgStatsBegin   message = "started call"
+	msgStatsEnd     message = "finished call"
+	msgStatsHeader  message = "received header"
+	msgStatsTrailer message = "sent trailer"
+)
+
+// NewStatsHandler returns the stats.Handler that logs the gRPC calls and connections, it's an alternative of interceptors.
+// The finished calls are logged with the same fields and options as interceptors, plus the payload wire lengths.
+// The call begin, the headers and trailers are logged with Debug level, the connections with Info level.
+// Unlike the server interceptors, it doesn't populate the logger into the context of handlers.
+func NewStatsHandler(logger zerolog.Logger, opts ...Option) stats.Handler {
+	return &statsHandler{logger: logger, o: evaluateOptions(opts)}
+}
+
+type statsHandler struct {
+	logger zerolog.Logger
+	o      *options
+}
+
+type rpcStatsKey struct{}
+
+type rpcStats struct {
+	method           string
+	start, wallStart time.Time
+	inWire, outWire  int64
+	inMsgs, outMsgs  int64
+	// compression is the compressor of received messages, or of sent ones for the client if the response has no header
+	compression atomic.Value
+}
+
+type connStatsKey struct{}
+
+type connStats struct {
+	info  *stats.ConnTagInfo
+	start time.Time
+}
+
+func (h *statsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcStatsKey{}, &rpcStats{method: info.FullMethodName, start: h.o.clock.Now(), wallStart: time.Now()})
+}
+
+func (h *statsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	r, ok := ctx.Value(rpcStatsKey{}).(*rpcStats)
+	if !ok {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.Begin:
+		l := h.callLog(ctx, r, s.IsClient()).Logger()
+		l.Debug().Msg(string(msgStatsBegin))
+	case *stats.OutHeader:
+		if s.Client && r.compression.Load() == nil {
+			r.compression.Store(compressionName(s.Compression))
+		}
+	case *stats.InHeader:
+		r.compression.Store(compressionName(s.Compression))
+		with := h.o.fields.stats(h.callLog(ctx, r, s.Client), "header.wire_length", int64(s.WireLength))
+		if s.Compression != "" {
+			with = h.o.fields.compression(with, s.Compression)
+		}
+		l := h.o.fields.addresses(with, s.RemoteAddr, s.LocalAddr).Logger()
+		l.Debug().Msg(string(msgStatsHeader))
+	case *stats.OutTrailer:
+		l := h.o.fields.stats(h.callLog(ctx, r, s.Client), "trailer.wire_length", int64(s.WireLength)).Logger()
+		l.Debug().Msg(string(msgStatsTrailer))
+	case *stats.InPayload:
+		atomic.AddInt64(&r.inWire, int64(s.WireLength))
+		atomic.AddInt64(&r.inMsgs, 1)
+	case *stats.OutPayload:
+		atomic.AddInt64(&r.outWire, int64(s.WireLength))
+		atomic.AddInt64(&r.outMsgs, 1)
+	case *stats.End:
+		if !h.o.shouldLog(r.method, s.Error) || h.o.infra.suppressed(h.logger, h.o, r.method, s.Error) {
+			return
+		}
+		reqWire, resWire := atomic.LoadInt64(&r.inWire), atomic.LoadInt64(&r.outWire)
+		reqMsgs, resMsgs := atomic.LoadInt64(&r.inMsgs), atomic.LoadInt64(&r.outMsgs)
+		if s.Client {
+			reqWire, resWire, reqMsgs, resMsgs = resWire, reqWire, resMsgs, reqMsgs
+		}
+		with := h.callLog(ctx, r, s.Client)
+		with = h.o.fields.stats(with, "request.wire_length", reqWire)
+		with = h.o.fields.stats(with, "response.wire_length", resWire)
+		with = h.o.fields.stats(with, "request.messages", reqMsgs)
+		with = h.o.fields.stats(with, "response.messages", resMsgs)
+		if c, ok := r.compression.Load().(string); ok {
+			with = h.o.fields.compression(with, c)
+		}
+		with = h.o.fields.messageSize(with, s.Error, callLimits{})
+		h.o.logCall(with, CallInfo{Context: ctx, FullMethod: r.method, Kind: CallStats, Client: s.Client, Err: s.Error, Start: r.start, Duration: h.o.clock.Since(r.start), wallStart: r.wallStart})
+	}
+}
+
+// callLog returns the logger context of the call with fields of interceptors
+func (h *statsHandler) callLog(ctx context.Context, r *rpcStats, client bool) zerolog.Context {
+	if client {
+		return logMetadata(initLog(ctx, h.o.loggerFor(h.logger, r.method), r.method, &h.o.fields), outgoingMetadata(ctx), h.o.metadataKeys())
+	}
+	md := incomingMetadata(ctx)
+	return logAttempt(logMetadata(initLog(ctx, h.o.loggerFor(h.logger, r.method), r.method, &h.o.fields), md, h.o.metadataKeys()), md)
+}
+
+func (h *statsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connStatsKey{}, &connStats{info: info, start: h.o.clock.Now()})
+}
+
+func (h *statsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	c, ok := ctx.Value(connStatsKey{}).(*connStats)
+	if !ok {
+		return
+	}
+	with := h.o.fields.addresses(h.logger.With().Bool("grpc.client", s.IsClient()), c.info.RemoteAddr, c.info.LocalAddr)
+	level := h.o.levelFunc(codes.OK)
+	switch s.(type) {
+	case *stats.ConnBegin:
+		l := with.Logger()
+		l.WithLevel(level).Msg(string(msgConnOpen))
+	case *stats.ConnEnd:
+		l := millisField(with, "grpc.conn.time_ms", h.o.clock.Since(c.start)).Logger()
+		l.WithLevel(level).Msg(string(msgConnClose))
+	}
+}
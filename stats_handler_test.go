@@ -0,0 +1,81 @@
+package grpc_zerolog_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/pereslava/grpc_zerolog/grpczerologtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const checkMethod = "/grpc.health.v1.Health/Check"
+
+func TestStatsHandler(t *testing.T) {
+	for name, names := range map[string]grpc_zerolog.FieldNames{
+		"default": grpc_zerolog.DefaultFieldNames,
+		"otel":    grpc_zerolog.OTelFieldNames,
+	} {
+		t.Run(name, func(t *testing.T) {
+			serverLogs, clientLogs := grpczerologtest.NewRecorderWithFieldNames(names), grpczerologtest.NewRecorderWithFieldNames(names)
+			opt := grpc_zerolog.WithFieldNames(names)
+
+			lis := bufconn.Listen(1 << 20)
+			s := grpc.NewServer(grpc.StatsHandler(grpc_zerolog.NewStatsHandler(serverLogs.Logger(), opt)))
+			healthpb.RegisterHealthServer(s, health.NewServer())
+			go s.Serve(lis)
+			defer s.Stop()
+
+			cc, err := grpc.Dial("bufconn",
+				grpc.WithInsecure(),
+				grpc.WithStatsHandler(grpc_zerolog.NewStatsHandler(clientLogs.Logger(), opt)),
+				grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cc.Close()
+			if _, err := healthpb.NewHealthClient(cc).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+				t.Fatal(err)
+			}
+
+			for side, r := range map[string]*grpczerologtest.Recorder{"server": serverLogs, "client": clientLogs} {
+				e := waitFinishedCall(t, r, checkMethod)
+				if e.Code != codes.OK {
+					t.Errorf("%s: got code %v, want OK", side, e.Code)
+				}
+				for _, field := range []string{"request.wire_length", "response.wire_length", "request.messages", "response.messages"} {
+					if v, _ := e.Fields[names.Stats+field].(float64); v <= 0 {
+						t.Errorf("%s: got %s %v, want positive: %v", side, names.Stats+field, e.Fields[names.Stats+field], e.Fields)
+					}
+				}
+
+				var header bool
+				for _, e := range r.CallEntries(checkMethod) {
+					if e.Message != "received header" {
+						continue
+					}
+					header = true
+					fields := []string{names.Stats + "header.wire_length"}
+					if side == "server" {
+						// gRPC sets the address of received headers only on the server
+						fields = append(fields, names.PeerAddress, names.LocalAddress)
+					}
+					for _, field := range fields {
+						if _, ok := e.Fields[field]; !ok {
+							t.Errorf("%s: %s not logged: %v", side, field, e.Fields)
+						}
+					}
+				}
+				if !header {
+					t.Errorf("%s: no header logged, captured:\n%s", side, r)
+				}
+			}
+		})
+	}
+}
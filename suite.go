@@ -0,0 +1,154 @@
+package grpc_zerolog
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+// SuiteOption used to configure the interceptors installed by ServerOptions and DialOptions
+type SuiteOption func(*suiteOptions)
+
+// WithLogOptions sets the options of logging interceptors
+func WithLogOptions(opts ...Option) SuiteOption {
+	return func(o *suiteOptions) {
+		o.options = append(o.options, opts...)
+	}
+}
+
+// WithPayloadOptions sets the options of payload interceptors
+func WithPayloadOptions(opts ...PayloadOption) SuiteOption {
+	return func(o *suiteOptions) {
+		o.payloadOptions = append(o.payloadOptions, opts...)
+	}
+}
+
+// WithoutPayload disables the payload interceptors
+func WithoutPayload() SuiteOption {
+	return func(o *suiteOptions) {
+		o.payload = false
+	}
+}
+
+// WithoutRecovery disables the recovery interceptors of server
+func WithoutRecovery() SuiteOption {
+	return func(o *suiteOptions) {
+		o.recovery = false
+	}
+}
+
+// WithAttempts installs the attempt handler of client calls by DialOptions, see NewAttemptHandler.
+// gRPC allows only one stats handler per connection, so it replaces the stats handler set by other dial options.
+func WithAttempts() SuiteOption {
+	return func(o *suiteOptions) {
+		o.attempts = true
+	}
+}
+
+// WithGrpcLogger replaces the logger of gRPC internals by the logger of ServerOptions or DialOptions, see ReplaceGrpcLogger.
+// The logger is replaced once per option, when the first of ServerOptions and DialOptions given the option is called,
+// the function restoring the replaced logger is stored to restore then. Defer the restore as the one of ReplaceGrpcLogger.
+func WithGrpcLogger(restore *func(), opts ...BridgeOption) SuiteOption {
+	b := &suiteBridge{restore: restore, opts: opts}
+	return func(o *suiteOptions) {
+		o.bridge = b
+	}
+}
+
+// suiteBridge replaces the gRPC logger once for all suites built with the same WithGrpcLogger option
+type suiteBridge struct {
+	once    sync.Once
+	restore *func()
+	opts    []BridgeOption
+}
+
+func (b *suiteBridge) replace(logger zerolog.Logger) {
+	if b == nil {
+		return
+	}
+	b.once.Do(func() {
+		restore := ReplaceGrpcLogger(logger, b.opts...)
+		if b.restore != nil {
+			*b.restore = restore
+		}
+	})
+}
+
+type suiteOptions struct {
+	options        []Option
+	payloadOptions []PayloadOption
+	payload        bool
+	recovery       bool
+	attempts       bool
+	bridge         *suiteBridge
+	fields         FieldNames
+}
+
+// evaluateSuiteOptions evaluates the options, the payload interceptors get the field names of logging interceptors
+// unless WithPayloadOptions sets them by WithPayloadFieldNames
+func evaluateSuiteOptions(opts []SuiteOption) *suiteOptions {
+	o := &suiteOptions{payload: true, recovery: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.fields = evaluateOptions(o.options).fields
+	o.payloadOptions = append([]PayloadOption{WithPayloadFieldNames(o.fields)}, o.payloadOptions...)
+	return o
+}
+
+// SuiteFieldNames returns the field names of call statements logged by the interceptors of the options
+func SuiteFieldNames(opts ...SuiteOption) FieldNames {
+	return evaluateSuiteOptions(opts).fields
+}
+
+// ServerOptions returns the server options installing the interceptors in order: payload, logging and recovery.
+// So the logging interceptor logs the panics recovered as codes.Internal, and the recovery uses the logger from context.
+// The logger of gRPC internals is replaced only if WithGrpcLogger is given.
+func ServerOptions(logger zerolog.Logger, opts ...SuiteOption) []grpc.ServerOption {
+	o := evaluateSuiteOptions(opts)
+	o.bridge.replace(logger)
+
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if o.payload {
+		unary = append(unary, NewPayloadUnaryServerInterceptor(logger, o.payloadOptions...))
+		stream = append(stream, NewPayloadStreamServerInterceptor(logger, o.payloadOptions...))
+	}
+	unary = append(unary, NewUnaryServerInterceptor(logger, o.options...))
+	stream = append(stream, NewStreamServerInterceptor(logger, o.options...))
+	if o.recovery {
+		unary = append(unary, NewRecoveryUnaryServerInterceptor(logger))
+		stream = append(stream, NewRecoveryStreamServerInterceptor(logger))
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
+
+// DialOptions returns the dial options installing the interceptors in order: payload and logging.
+// The logger of gRPC internals is replaced only if WithGrpcLogger is given.
+func DialOptions(logger zerolog.Logger, opts ...SuiteOption) []grpc.DialOption {
+	o := evaluateSuiteOptions(opts)
+	o.bridge.replace(logger)
+
+	var unary []grpc.UnaryClientInterceptor
+	var stream []grpc.StreamClientInterceptor
+	if o.payload {
+		unary = append(unary, NewPayloadUnaryClientInterceptor(logger, o.payloadOptions...))
+		stream = append(stream, NewPayloadStreamClientInterceptor(logger, o.payloadOptions...))
+	}
+	unary = append(unary, NewUnaryClientInterceptor(logger, o.options...))
+	stream = append(stream, NewStreamClientInterceptor(logger, o.options...))
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(stream...),
+	}
+	if o.attempts {
+		dialOpts = append(dialOpts, grpc.WithStatsHandler(NewAttemptHandler()))
+	}
+	return dialOpts
+}